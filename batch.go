@@ -1,7 +1,9 @@
 package espsdk
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,66 +13,178 @@ import (
 // A Batch is a container for Contributions of the same type and
 // any Releases that may be associated with them.
 type Batch struct {
-	AssignmentID                     string     `json:"assignment_id,omitempty"`
-	BatchTags                        []string   `json:"batch_tags,omitempty"`
-	BriefID                          string     `json:"brief_id,omitempty"`
-	ContributionsAwaitingReviewCount int        `json:"contributions_awaiting_review_count,omitempty"`
-	ContributionsCount               int        `json:"contributions_count,omitempty"`
-	CreatedAt                        *time.Time `json:"created_at,omitempty"`
-	CreatedBy                        string     `json:"created_by,omitempty"`
-	CreatorIstockUsername            string     `json:"creator_istock_username,omitempty"`
-	EventID                          string     `json:"event_id,omitempty"`
-	ID                               string     `json:"id,omitempty"`
-	IsGetty                          bool       `json:"is_getty,omitempty"`
-	IsIstock                         bool       `json:"is_istock,omitempty"`
-	IstockExclusive                  bool       `json:"istock_exclusive,omitempty"`
-	LastContributionSubmittedAt      *time.Time `json:"last_contribution_submitted_at,omitempty"`
-	LastSubmittedAt                  *time.Time `json:"last_submitted_at,omitempty"`
-	Note                             string     `json:"note,omitempty"`
-	ProfileID                        string     `json:"profile_id,omitempty"`
-	ReviewedContributionsCount       int        `json:"reviewed_contributions_count,omitempty"`
-	RevisableContributionsCount      int        `json:"revisable_contributions_count,omitempty"`
-	SaveExtractedMetadata            bool       `json:"save_extracted_metadata,omitempty"`
-	Status                           string     `json:"status,omitempty"`
-	SubmissionName                   string     `json:"submission_name,omitempty"`
-	SubmissionType                   string     `json:"submission_type,omitempty"`
-	SubmittedContributionsCount      int        `json:"submitted_contributions_count,omitempty"`
-	UpdatedAt                        *time.Time `json:"updated_at,omitempty"`
-	UserID                           string     `json:"user_id,omitempty"`
+	AssignmentID                     string      `json:"assignment_id,omitempty"`
+	BatchTags                        []string    `json:"batch_tags,omitempty"`
+	BriefID                          string      `json:"brief_id,omitempty"`
+	ContributionsAwaitingReviewCount int         `json:"contributions_awaiting_review_count,omitempty"`
+	ContributionsCount               int         `json:"contributions_count,omitempty"`
+	CreatedAt                        *time.Time  `json:"created_at,omitempty"`
+	CreatedBy                        string      `json:"created_by,omitempty"`
+	CreatorIstockUsername            string      `json:"creator_istock_username,omitempty"`
+	EventID                          string      `json:"event_id,omitempty"`
+	ID                               string      `json:"id,omitempty"`
+	IsGetty                          bool        `json:"is_getty,omitempty"`
+	IsIstock                         bool        `json:"is_istock,omitempty"`
+	IstockExclusive                  bool        `json:"istock_exclusive,omitempty"`
+	LastContributionSubmittedAt      *time.Time  `json:"last_contribution_submitted_at,omitempty"`
+	LastSubmittedAt                  *time.Time  `json:"last_submitted_at,omitempty"`
+	Note                             string      `json:"note,omitempty"`
+	ProfileID                        string      `json:"profile_id,omitempty"`
+	ReviewedContributionsCount       int         `json:"reviewed_contributions_count,omitempty"`
+	RevisableContributionsCount      int         `json:"revisable_contributions_count,omitempty"`
+	SaveExtractedMetadata            bool        `json:"save_extracted_metadata,omitempty"`
+	Status                           BatchStatus `json:"status,omitempty"`
+	SubmissionName                   string      `json:"submission_name,omitempty"`
+	SubmissionType                   string      `json:"submission_type,omitempty"`
+	SubmittedContributionsCount      int         `json:"submitted_contributions_count,omitempty"`
+	UpdatedAt                        *time.Time  `json:"updated_at,omitempty"`
+	UserID                           string      `json:"user_id,omitempty"`
 }
 
 // Index requests a list of all Batches for the account.
-func (b Batch) Index(client sleepwalker.RESTClient) BatchList {
+func (b Batch) Index(client sleepwalker.RESTClient) (BatchList, error) {
+	return b.IndexContext(context.Background(), contextClient(client))
+}
+
+// IndexContext is like Index but sends the request with the provided
+// context.
+func (b Batch) IndexContext(ctx context.Context, client ContextClient) (BatchList, error) {
 	desc := "Batch.Index"
-	result, err := client.Get(b)
-	if err != nil {
+	result, err := client.GetContext(ctx, b)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return BatchList{}, err
+	}
+	result.Log().Info(desc)
+	return BatchList{}.Unmarshal(result.Payload)
+}
+
+// Errors returned by the Batch methods before any request is sent.
+var (
+	ErrInvalidBatchName = errors.New("espsdk: a Batch needs a SubmissionName")
+	ErrInvalidBatchType = errors.New("espsdk: invalid SubmissionType")
+	ErrMissingID        = errors.New("espsdk: an ID is required")
+)
+
+// Validate runs NameIsValid and TypeIsValid, returning ErrInvalidBatchName
+// or ErrInvalidBatchType if either fails.
+func (b Batch) Validate() error { return b.validate(defaultSubmissionTypes) }
+
+// validateFor is like Validate, but checks the SubmissionType against those
+// known to the client if it is a Client.
+func (b Batch) validateFor(client ContextClient) error {
+	return b.validate(submissionTypesOf(client))
+}
+
+func (b Batch) validate(types *submissionTypeSet) error {
+	if !b.NameIsValid() {
+		return ErrInvalidBatchName
+	}
+	if _, ok := types.lookup(b.SubmissionType); !ok {
+		return fmt.Errorf("%w %q (valid types: %v)", ErrInvalidBatchType, b.SubmissionType, types.names())
+	}
+	return nil
+}
+
+// submissionTypesOf returns the SubmissionTypes known to the client if it
+// is a Client, and the built-in ones otherwise.
+func submissionTypesOf(client ContextClient) *submissionTypeSet {
+	if c, ok := client.(interface{ submissionTypes() *submissionTypeSet }); ok {
+		return c.submissionTypes()
+	}
+	return defaultSubmissionTypes
+}
+
+// Create validates the Batch and asks ESP to create it, returning the Batch
+// as saved, including its ID. The SubmissionType must be one known to the
+// client, if it is a Client, or a built-in one.
+func (b Batch) Create(ctx context.Context, client ContextClient) (*Batch, error) {
+	desc := "Batch.Create"
+	if err := b.validateFor(client); err != nil {
+		return nil, err
+	}
+	b.ID = ""
+	result, err := client.CreateContext(ctx, b)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return nil, err
+	}
+	result.Log().Info(desc)
+	return Batch{}.Unmarshal(result.Payload)
+}
+
+// Get requests the Batch with the receiver's ID.
+func (b Batch) Get(ctx context.Context, client ContextClient) (*Batch, error) {
+	desc := "Batch.Get"
+	if b.ID == "" {
+		return nil, ErrMissingID
+	}
+	result, err := client.GetContext(ctx, b)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return nil, err
+	}
+	result.Log().Info(desc)
+	return Batch{}.Unmarshal(result.Payload)
+}
+
+// Update validates the Batch as Create does and replaces the saved Batch
+// with the same ID, returning the Batch as saved.
+func (b Batch) Update(ctx context.Context, client ContextClient) (*Batch, error) {
+	desc := "Batch.Update"
+	if b.ID == "" {
+		return nil, ErrMissingID
+	}
+	if err := b.validateFor(client); err != nil {
+		return nil, err
+	}
+	result, err := client.PutContext(ctx, BatchUpdate{b}, b.Path())
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
-		return BatchList{}
+		return nil, err
+	}
+	result.Log().Info(desc)
+	return Batch{}.Unmarshal(result.Payload)
+}
+
+// Delete deletes the Batch with the receiver's ID.
+func (b Batch) Delete(ctx context.Context, client ContextClient) error {
+	desc := "Batch.Delete"
+	if b.ID == "" {
+		return ErrMissingID
 	}
-	if result.StatusCode == 404 {
+	result, err := client.DeleteContext(ctx, b)
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
-		return BatchList{}
+		return err
 	}
 	result.Log().Info(desc)
-	batchList, _ := BatchList{}.Unmarshal(result.Payload)
-	return batchList
+	return nil
 }
 
 // NameIsValid provides validation for a proposed SubmissionName.
 func (b Batch) NameIsValid() bool { return len(b.SubmissionName) > 0 }
 
-// TypeIsValid reports whether a proposed type is valid for ESP.
-func (b *Batch) TypeIsValid() bool { return batchTypeIsValid[b.SubmissionType] }
+// TypeIsValid reports whether a proposed type is one of the built-in
+// SubmissionTypes. TypeIsValidFor also knows the live list from
+// Client.RefreshSubmissionTypes.
+func (b *Batch) TypeIsValid() bool { return b.TypeIsValidFor(nil) }
 
-// ValidTypes are the BatchTypes supported by ESP.
-func (b Batch) ValidTypes() []string {
-	keys := make([]string, len(batchTypeIsValid))
-	i := 0
-	for k := range batchTypeIsValid {
-		keys[i] = k
-		i++
-	}
-	return keys
+// TypeIsValidFor reports whether a proposed type is one that Create and
+// Update accept when given the client: one known to it if it is a Client,
+// or a built-in one.
+func (b Batch) TypeIsValidFor(client ContextClient) bool {
+	_, ok := submissionTypesOf(client).lookup(b.SubmissionType)
+	return ok
+}
+
+// ValidTypes are the built-in BatchTypes supported by ESP, sorted.
+func (b Batch) ValidTypes() []string { return b.ValidTypesFor(nil) }
+
+// ValidTypesFor are the BatchTypes that Create and Update accept when given
+// the client, sorted.
+func (b Batch) ValidTypesFor(client ContextClient) []string {
+	return submissionTypesOf(client).names()
 }
 
 // Path returns the path for the Batch. If the Batch has no ID, Path returns
@@ -112,14 +226,6 @@ func (bu BatchUpdate) Marshal() ([]byte, error) {
 	return sleepwalker.Marshal(bu)
 }
 
-var batchTypeIsValid = map[string]bool{
-	"getty_creative_video":  true,
-	"getty_editorial_video": true,
-	"getty_creative_still":  true,
-	"getty_editorial_still": true,
-	"istock_creative_video": true,
-}
-
 // A BatchList matches the structure of the JSON payload returned
 // by the GET (all) Batches API endpoint.
 type BatchList struct {
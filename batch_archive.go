@@ -0,0 +1,275 @@
+package espsdk
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion is the version of the archive format written by
+// BatchArchive.Write. Archives with a newer version cannot be read.
+const ArchiveVersion = 1
+
+// The names of the entries in an archive file.
+const (
+	archiveEntry     = "batch.json"
+	thumbnailsPrefix = "thumbnails/"
+)
+
+// A BatchArchive is a snapshot of a Batch with its Releases and
+// Contributions, which can be saved to a file and imported into another
+// account or environment.
+type BatchArchive struct {
+	Version       int            `json:"version"`
+	ExportedAt    time.Time      `json:"exported_at"`
+	Batch         Batch          `json:"batch"`
+	Releases      []Release      `json:"releases"`
+	Contributions []Contribution `json:"contributions"`
+
+	// Thumbnails holds the thumbnail images of the Contributions by
+	// Contribution ID, if they were exported. They are kept for reference
+	// only: ESP renders thumbnails from uploaded files, so they are not
+	// sent on import.
+	Thumbnails map[string][]byte `json:"-"`
+}
+
+// ExportOptions controls what ExportBatch includes in a BatchArchive.
+type ExportOptions struct {
+	// SkipReleases and SkipContributions leave out the Batch's Releases or
+	// Contributions.
+	SkipReleases      bool
+	SkipContributions bool
+
+	// Thumbnails downloads the image at each Contribution's ThumbnailURL.
+	Thumbnails bool
+
+	// HTTPClient is used to download thumbnails. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// ExportBatch requests the Batch with all of its Releases and Contributions
+// and returns them as a BatchArchive.
+func ExportBatch(ctx context.Context, client ContextClient, batchID string, opts ExportOptions) (*BatchArchive, error) {
+	batch, err := Batch{ID: batchID}.Get(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var releases []Release
+	if !opts.SkipReleases {
+		if releases, err = NewReleaseIterator(client, batchID).All(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var contributions []Contribution
+	if !opts.SkipContributions {
+		if contributions, err = NewContributionIterator(client, batchID).All(ctx); err != nil {
+			return nil, err
+		}
+	}
+	archive := &BatchArchive{
+		Version:       ArchiveVersion,
+		ExportedAt:    time.Now().UTC(),
+		Batch:         *batch,
+		Releases:      releases,
+		Contributions: contributions,
+		Thumbnails:    make(map[string][]byte),
+	}
+	if opts.Thumbnails {
+		httpClient := opts.HTTPClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		for _, c := range contributions {
+			if c.ThumbnailURL == "" {
+				continue
+			}
+			image, err := download(ctx, httpClient, c.ThumbnailURL)
+			if err != nil {
+				return nil, fmt.Errorf("espsdk: thumbnail of Contribution %s: %w", c.ID, err)
+			}
+			archive.Thumbnails[c.ID] = image
+		}
+	}
+	return archive, nil
+}
+
+func download(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Import recreates the archived Batch, then its Releases, then its
+// Contributions, as CloneBatch does, and returns the new objects' IDs
+// mapped from the archived ones. References to other objects in the
+// original account are cleared unless opts.KeepAccountReferences is set.
+// If a request fails part way, Import returns what it has created so far
+// along with the error.
+func (a BatchArchive) Import(ctx context.Context, client ContextClient, opts CloneOptions) (*BatchClone, error) {
+	settings := cloneBatch(a.Batch, opts.KeepAccountReferences)
+	if opts.SubmissionName != "" {
+		settings.SubmissionName = opts.SubmissionName
+	}
+	if opts.RewriteBatch != nil {
+		if err := opts.RewriteBatch(&settings); err != nil {
+			return nil, err
+		}
+	}
+	created, err := settings.Create(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	clone := &BatchClone{
+		Batch:           created,
+		ReleaseIDs:      make(map[string]string),
+		ContributionIDs: make(map[string]string),
+	}
+
+	if !opts.SkipReleases {
+		for _, original := range a.Releases {
+			r := cloneRelease(original, created.ID)
+			if opts.RewriteRelease != nil {
+				if err := opts.RewriteRelease(&r); err != nil {
+					return clone, err
+				}
+			}
+			saved, err := r.Create(ctx, client)
+			if err != nil {
+				return clone, fmt.Errorf("espsdk: copying Release %s: %w", original.ID, err)
+			}
+			clone.ReleaseIDs[original.ID] = saved.ID
+		}
+	}
+
+	if !opts.SkipContributions {
+		for _, original := range a.Contributions {
+			c := cloneContribution(original, created.ID, opts.KeepAccountReferences)
+			if opts.RewriteContribution != nil {
+				if err := opts.RewriteContribution(&c); err != nil {
+					return clone, err
+				}
+			}
+			saved, err := c.Create(ctx, client)
+			if err != nil {
+				return clone, fmt.Errorf("espsdk: copying Contribution %s: %w", original.ID, err)
+			}
+			clone.ContributionIDs[original.ID] = saved.ID
+		}
+	}
+	return clone, nil
+}
+
+// Write writes the archive to w as a zip file holding a JSON document and
+// any thumbnails.
+func (a BatchArchive) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	entry, err := zw.Create(archiveEntry)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(entry)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(a); err != nil {
+		return err
+	}
+	for id, image := range a.Thumbnails {
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: thumbnailsPrefix + id, Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(image); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Save writes the archive to the named file, replacing it if it exists.
+func (a BatchArchive) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := a.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadBatchArchive reads an archive written by BatchArchive.Write.
+func ReadBatchArchive(r io.ReaderAt, size int64) (*BatchArchive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	var archive *BatchArchive
+	thumbnails := make(map[string][]byte)
+	for _, f := range zr.File {
+		switch {
+		case f.Name == archiveEntry:
+			if archive, err = readArchiveEntry(f); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(f.Name, thumbnailsPrefix):
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			image, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			thumbnails[strings.TrimPrefix(f.Name, thumbnailsPrefix)] = image
+		}
+	}
+	if archive == nil {
+		return nil, fmt.Errorf("espsdk: not a batch archive: %s is missing", archiveEntry)
+	}
+	archive.Thumbnails = thumbnails
+	return archive, nil
+}
+
+func readArchiveEntry(f *zip.File) (*BatchArchive, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var archive BatchArchive
+	if err := json.NewDecoder(rc).Decode(&archive); err != nil {
+		return nil, err
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("espsdk: unsupported batch archive version %d", archive.Version)
+	}
+	return &archive, nil
+}
+
+// OpenBatchArchive reads the archive in the named file.
+func OpenBatchArchive(path string) (*BatchArchive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ReadBatchArchive(bytes.NewReader(data), int64(len(data)))
+}
@@ -0,0 +1,117 @@
+package espsdk_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestBatchArchiveRoundTrip(t *testing.T) {
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("jpeg:" + r.URL.Path))
+	}))
+	defer images.Close()
+
+	src := esptest.NewServer()
+	defer src.Close()
+	b := src.AddBatch(espsdk.Batch{SubmissionName: "Gala", SubmissionType: "getty_editorial_still", AssignmentID: "a1", EventID: "e1"})
+	rel := src.AddRelease(espsdk.Release{SubmissionBatchID: b.ID, ReleaseType: "Property"})
+	c := src.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Headline: "Red carpet", ThumbnailURL: images.URL + "/t1", EventID: "e1"})
+	src.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Headline: "No thumbnail"})
+
+	archive, err := espsdk.ExportBatch(context.Background(), src.Client(), b.ID, espsdk.ExportOptions{Thumbnails: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "gala.zip")
+	if err := archive.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := espsdk.OpenBatchArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != espsdk.ArchiveVersion || loaded.Batch.SubmissionName != "Gala" ||
+		len(loaded.Releases) != 1 || len(loaded.Contributions) != 2 {
+		t.Fatalf("got %+v", loaded)
+	}
+	if got := string(loaded.Thumbnails[c.ID]); got != "jpeg:/t1" || len(loaded.Thumbnails) != 1 {
+		t.Errorf("got thumbnails %v", loaded.Thumbnails)
+	}
+
+	// Import into a different account.
+	dst := esptest.NewServer()
+	defer dst.Close()
+	dst.AddBatch(espsdk.Batch{SubmissionName: "existing", SubmissionType: "getty_creative_still"})
+	imported, err := loaded.Import(context.Background(), dst.Client(), espsdk.CloneOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newID := imported.ContributionIDs[c.ID]
+	if newID == "" || imported.ReleaseIDs[rel.ID] == "" {
+		t.Fatalf("got %+v", imported)
+	}
+	var found bool
+	for _, got := range dst.Contributions(imported.Batch.ID) {
+		if got.ID == newID {
+			found = got.Headline == "Red carpet" && got.EventID == ""
+		}
+	}
+	if !found {
+		t.Errorf("Contribution %s was not imported as %s without its event", c.ID, newID)
+	}
+	if imported.Batch.AssignmentID != "" || imported.Batch.EventID != "" {
+		t.Errorf("references to the original account were imported: %+v", imported.Batch)
+	}
+
+	kept, err := loaded.Import(context.Background(), dst.Client(), espsdk.CloneOptions{KeepAccountReferences: true, SkipContributions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.Batch.AssignmentID != "a1" || kept.Batch.EventID != "e1" || len(dst.Contributions(kept.Batch.ID)) != 0 {
+		t.Errorf("got %+v", kept.Batch)
+	}
+}
+
+func TestExportBatchSkips(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "Gala", SubmissionType: "getty_editorial_still"})
+	srv.AddRelease(espsdk.Release{SubmissionBatchID: b.ID, ReleaseType: "Property"})
+	srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Headline: "Red carpet"})
+
+	archive, err := espsdk.ExportBatch(context.Background(), srv.Client(), b.ID, espsdk.ExportOptions{SkipReleases: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Releases) != 0 || len(archive.Contributions) != 1 {
+		t.Errorf("got %+v", archive)
+	}
+	archive, err = espsdk.ExportBatch(context.Background(), srv.Client(), b.ID, espsdk.ExportOptions{SkipContributions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Releases) != 1 || len(archive.Contributions) != 0 {
+		t.Errorf("got %+v", archive)
+	}
+}
+
+func TestReadBatchArchiveRejectsNewerVersions(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("batch.json")
+	w.Write([]byte(`{"version": 99, "batch": {}}`))
+	zw.Close()
+	_, err := espsdk.ReadBatchArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("got %v", err)
+	}
+}
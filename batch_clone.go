@@ -0,0 +1,149 @@
+package espsdk
+
+import "context"
+
+// CloneOptions controls how CloneBatch copies a Batch.
+type CloneOptions struct {
+	// SubmissionName names the new Batch. If empty, the original name is
+	// kept.
+	SubmissionName string
+
+	// SkipReleases and SkipContributions leave out the Batch's Releases or
+	// Contributions.
+	SkipReleases      bool
+	SkipContributions bool
+
+	// KeepAccountReferences copies the fields that refer to other objects
+	// in the original account: the AssignmentID, BriefID, EventID and
+	// ProfileID of the Batch and the EventID and PaidAssignmentID of its
+	// Contributions. BatchArchive.Import clears them unless it is set,
+	// since an archive may be imported into another account; CloneBatch
+	// always keeps them.
+	KeepAccountReferences bool
+
+	// RewriteBatch, RewriteRelease and RewriteContribution, if set, are
+	// called with each copy just before it is created, after the fields
+	// that cannot be copied have been cleared and it has been moved to the
+	// new Batch. Returning an error stops the clone.
+	RewriteBatch        func(*Batch) error
+	RewriteRelease      func(*Release) error
+	RewriteContribution func(*Contribution) error
+}
+
+// A BatchClone describes what CloneBatch or BatchArchive.Import created.
+// ReleaseIDs and ContributionIDs map the IDs of the originals to those of
+// their copies.
+type BatchClone struct {
+	Batch           *Batch
+	ReleaseIDs      map[string]string
+	ContributionIDs map[string]string
+}
+
+// CloneBatch creates a new Batch with the settings of an existing one, then
+// copies its Releases and the metadata of its Contributions into it.
+// Server-assigned fields, review state and uploaded files are not copied;
+// the copies of the Contributions need their files uploaded again. The
+// clone is in the same account, so references to other objects in it are
+// kept.
+//
+// If a request fails part way, CloneBatch returns what it has created so
+// far along with the error, so that the caller can finish or delete it.
+func CloneBatch(ctx context.Context, client ContextClient, batchID string, opts CloneOptions) (*BatchClone, error) {
+	archive, err := ExportBatch(ctx, client, batchID, ExportOptions{
+		SkipReleases:      opts.SkipReleases,
+		SkipContributions: opts.SkipContributions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	opts.KeepAccountReferences = true
+	return archive.Import(ctx, client, opts)
+}
+
+// cloneBatch returns the settings of the Batch that a new Batch can take
+// over, without its references to other objects in the account unless
+// keepRefs is set.
+func cloneBatch(b Batch, keepRefs bool) Batch {
+	clone := Batch{
+		AssignmentID:          b.AssignmentID,
+		BatchTags:             append([]string(nil), b.BatchTags...),
+		BriefID:               b.BriefID,
+		EventID:               b.EventID,
+		IsGetty:               b.IsGetty,
+		IsIstock:              b.IsIstock,
+		IstockExclusive:       b.IstockExclusive,
+		Note:                  b.Note,
+		ProfileID:             b.ProfileID,
+		SaveExtractedMetadata: b.SaveExtractedMetadata,
+		SubmissionName:        b.SubmissionName,
+		SubmissionType:        b.SubmissionType,
+	}
+	if !keepRefs {
+		clone.AssignmentID = ""
+		clone.BriefID = ""
+		clone.EventID = ""
+		clone.ProfileID = ""
+	}
+	return clone
+}
+
+// cloneRelease returns a copy of the Release for the given Batch. Its
+// ExternalFileLocation is kept so that ESP can fetch the document again,
+// but the record of the original upload is not.
+func cloneRelease(r Release, batchID string) Release {
+	r.ID = ""
+	r.SubmissionBatchID = batchID
+	r.FilePath = ""
+	r.StorageURL = ""
+	r.UploadID = 0
+	r.ModelEthnicities = append([]string(nil), r.ModelEthnicities...)
+	return r
+}
+
+// cloneContribution returns a copy of the Contribution's metadata for the
+// given Batch, without its file or anything ESP derives from the file or
+// from review, and without its references to other objects in the account
+// unless keepRefs is set.
+func cloneContribution(c Contribution, batchID string, keepRefs bool) Contribution {
+	c.ID = ""
+	c.SubmissionBatchID = batchID
+	if !keepRefs {
+		c.EventID = ""
+		c.PaidAssignmentID = ""
+	}
+
+	// Server-assigned and review state.
+	c.CreatedAt = nil
+	c.UpdatedAt = nil
+	c.Errors = nil
+	c.InactiveDate = nil
+	c.MasterID = ""
+	c.PicscoutSuggestions = nil
+	c.PublishedAt = nil
+	c.PulledReason = ""
+	c.Rank = 0
+	c.ReadyForSale = false
+	c.Status = ""
+	c.Submittable = false
+	c.SubmittedAt = nil
+	c.SubmittedToReviewAt = ""
+	c.UserMetadataValid = false
+
+	// The file and what was extracted from it.
+	c.ExternalFileLocation = ""
+	c.ExtractedMetadataPresent = false
+	c.FileName = ""
+	c.FilePath = ""
+	c.FileUploaded = false
+	c.FinalBucket = ""
+	c.ImageHeight = 0
+	c.ImageWidth = 0
+	c.MetadataExtractionStartedAt = nil
+	c.MetadataExtractionTimeout = false
+	c.MimeType = ""
+	c.StorageURL = ""
+	c.ThumbnailURL = ""
+	c.UploadBucket = ""
+	c.UploadID = ""
+	return c
+}
@@ -0,0 +1,64 @@
+package espsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestCloneBatch(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	orig := srv.AddBatch(espsdk.Batch{
+		SubmissionName: "Marathon", SubmissionType: "getty_editorial_still",
+		Note: "finish line", BatchTags: []string{"sports"}, Status: espsdk.BatchReviewed,
+		AssignmentID: "a1",
+	})
+	rel := srv.AddRelease(espsdk.Release{SubmissionBatchID: orig.ID, ReleaseType: "Model", ExternalFileLocation: "https://example.com/r.pdf", UploadID: 7})
+	c := srv.AddContribution(espsdk.Contribution{
+		SubmissionBatchID: orig.ID, Headline: "Winner crosses line", City: "Boston",
+		FileName: "IMG_1.jpg", ThumbnailURL: "https://example.com/t.jpg", Status: "approved",
+	})
+
+	clone, err := espsdk.CloneBatch(context.Background(), srv.Client(), orig.ID, espsdk.CloneOptions{
+		SubmissionName: "Marathon (re-shoot)",
+		RewriteContribution: func(c *espsdk.Contribution) error {
+			c.City = "Cambridge"
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := clone.Batch
+	if b.ID == orig.ID || b.SubmissionName != "Marathon (re-shoot)" || b.Note != "finish line" ||
+		b.Status != espsdk.BatchOpen || b.AssignmentID != "a1" {
+		t.Errorf("got %+v", b)
+	}
+
+	releases := srv.Releases(b.ID)
+	if len(releases) != 1 || releases[0].ID != clone.ReleaseIDs[rel.ID] ||
+		releases[0].ExternalFileLocation != rel.ExternalFileLocation || releases[0].UploadID != 0 {
+		t.Errorf("got releases %+v", releases)
+	}
+	contributions := srv.Contributions(b.ID)
+	if len(contributions) != 1 || contributions[0].ID != clone.ContributionIDs[c.ID] {
+		t.Fatalf("got contributions %+v", contributions)
+	}
+	got := contributions[0]
+	if got.Headline != c.Headline || got.City != "Cambridge" || got.FileName != "" ||
+		got.ThumbnailURL != "" || got.Status != "pending" {
+		t.Errorf("got %+v", got)
+	}
+
+	stop := errors.New("stop")
+	clone, err = espsdk.CloneBatch(context.Background(), srv.Client(), orig.ID, espsdk.CloneOptions{
+		RewriteRelease: func(*espsdk.Release) error { return stop },
+	})
+	if err != stop || clone == nil || clone.Batch == nil {
+		t.Errorf("a failed clone should report the Batch it created: %v, %+v", err, clone)
+	}
+}
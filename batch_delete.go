@@ -0,0 +1,125 @@
+package espsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DeleteOptions controls which Batches DeleteBatches deletes.
+type DeleteOptions struct {
+	// Match selects the Batches to delete. It is required, so that no call
+	// deletes every Batch by accident; use AnyBatch to select them all.
+	Match func(Batch) bool
+
+	// Limit stops after this many Batches have been selected, newest
+	// first. Zero means no limit.
+	Limit int
+
+	// DryRun reports what would be deleted without deleting anything.
+	DryRun bool
+
+	// Force deletes Batches with submitted Contributions, which are
+	// otherwise skipped. Without it, the Contributions of each Batch are
+	// requested to confirm that none has been submitted, since the count
+	// in the Batch index may be stale or missing, and a Batch whose
+	// Contributions cannot be requested is skipped.
+	Force bool
+}
+
+// AnyBatch is a DeleteOptions.Match predicate that selects every Batch.
+func AnyBatch(Batch) bool { return true }
+
+// A DeletionReport lists what DeleteBatches deleted, or would have deleted
+// in a dry run, and what it skipped.
+type DeletionReport struct {
+	DryRun  bool
+	Deleted []Batch
+	Skipped []SkippedBatch
+}
+
+// A SkippedBatch is a Batch that matched but was not deleted, and why.
+type SkippedBatch struct {
+	Batch  Batch
+	Reason string
+}
+
+// ErrNoMatch is returned by DeleteBatches when DeleteOptions.Match is not
+// set.
+var ErrNoMatch = errors.New("espsdk: DeleteOptions.Match is required")
+
+// DeleteBatches deletes the Batches selected by opts, newest first. A Batch
+// with submitted Contributions is skipped unless opts.Force is set. If a
+// deletion fails, DeleteBatches stops and returns the report so far with
+// the error.
+//
+// To delete only the newest Batch:
+//
+//	report, err := espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{
+//		Match: espsdk.AnyBatch,
+//		Limit: 1,
+//	})
+func DeleteBatches(ctx context.Context, client ContextClient, opts DeleteOptions) (*DeletionReport, error) {
+	if opts.Match == nil {
+		return nil, ErrNoMatch
+	}
+	report := &DeletionReport{DryRun: opts.DryRun}
+	var selected []Batch
+	it := NewBatchIterator(client)
+	for (opts.Limit <= 0 || len(selected) < opts.Limit) && it.Next(ctx) {
+		if opts.Match(it.Batch()) {
+			selected = append(selected, it.Batch())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return report, err
+	}
+
+	for _, b := range selected {
+		if b.ID == "" {
+			report.Skipped = append(report.Skipped, SkippedBatch{b, "it has no ID"})
+			continue
+		}
+		if !opts.Force {
+			n, err := countSubmitted(ctx, client, b)
+			if err != nil {
+				report.Skipped = append(report.Skipped, SkippedBatch{b,
+					fmt.Sprintf("its Contributions could not be checked: %v", err)})
+				continue
+			}
+			if n > 0 {
+				report.Skipped = append(report.Skipped, SkippedBatch{b,
+					fmt.Sprintf("it has %d submitted Contributions", n)})
+				continue
+			}
+		}
+		if !opts.DryRun {
+			if err := b.Delete(ctx, client); err != nil {
+				return report, err
+			}
+		}
+		report.Deleted = append(report.Deleted, b)
+	}
+	return report, nil
+}
+
+// countSubmitted returns the number of the Batch's Contributions that have
+// been submitted. The count in the Batch is trusted only if it is not zero.
+// A Contribution with a SubmittedAt or any status but pending counts, so
+// that in doubt the Batch is kept.
+func countSubmitted(ctx context.Context, client ContextClient, b Batch) (int, error) {
+	if b.SubmittedContributionsCount > 0 {
+		return b.SubmittedContributionsCount, nil
+	}
+	contributions, err := NewContributionIterator(client, b.ID).All(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, c := range contributions {
+		if c.SubmittedAt != nil || c.Status != "" && c.Status != "pending" {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,104 @@
+package espsdk_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestDeleteBatches(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	keep := srv.AddBatch(espsdk.Batch{SubmissionName: "keep", SubmissionType: "getty_creative_still"})
+	scratch := srv.AddBatch(espsdk.Batch{SubmissionName: "scratch 1", SubmissionType: "getty_creative_still"})
+	submitted := srv.AddBatch(espsdk.Batch{SubmissionName: "scratch 2", SubmissionType: "getty_creative_still"})
+	srv.AddContribution(espsdk.Contribution{SubmissionBatchID: submitted.ID, Status: "submitted"})
+	isScratch := func(b espsdk.Batch) bool { return strings.HasPrefix(b.SubmissionName, "scratch") }
+
+	if _, err := espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{}); err != espsdk.ErrNoMatch {
+		t.Errorf("got %v, want %v", err, espsdk.ErrNoMatch)
+	}
+
+	report, err := espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{Match: isScratch, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 1 || report.Deleted[0].ID != scratch.ID ||
+		len(report.Skipped) != 1 || report.Skipped[0].Batch.ID != submitted.ID {
+		t.Errorf("got %+v", report)
+	}
+	if len(srv.Batches()) != 3 {
+		t.Error("a dry run should not delete anything")
+	}
+
+	report, err = espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{Match: isScratch, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 2 || len(report.Skipped) != 0 {
+		t.Errorf("got %+v", report)
+	}
+	if batches := srv.Batches(); len(batches) != 1 || batches[0].ID != keep.ID {
+		t.Errorf("got %+v", batches)
+	}
+
+	// The replacement for DeleteLastBatch.
+	newest := srv.AddBatch(espsdk.Batch{SubmissionName: "newest", SubmissionType: "getty_creative_still"})
+	report, err = espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{Match: espsdk.AnyBatch, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 1 || report.Deleted[0].ID != newest.ID {
+		t.Errorf("got %+v", report)
+	}
+
+	// With no Batches left, nothing is deleted rather than an empty Batch.
+	espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{Match: espsdk.AnyBatch})
+	report, err = espsdk.DeleteBatches(ctx, client, espsdk.DeleteOptions{Match: espsdk.AnyBatch, Limit: 1})
+	if err != nil || len(report.Deleted) != 0 {
+		t.Errorf("got %+v, %v", report, err)
+	}
+}
+
+func TestDeleteBatchesChecksContributionsWhenCountIsMissing(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	var batches []espsdk.Batch
+	for _, name := range []string{"3", "2", "1"} {
+		batches = append(batches, srv.AddBatch(espsdk.Batch{SubmissionName: name, SubmissionType: "getty_creative_still"}))
+	}
+	unchecked, submitted, empty := batches[1], batches[2], batches[0]
+	srv.AddContribution(espsdk.Contribution{SubmissionBatchID: submitted.ID, Status: "submitted"})
+	srv.AddContribution(espsdk.Contribution{SubmissionBatchID: empty.ID})
+	for _, b := range batches {
+		// The index does not report submitted_contributions_count.
+		srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.SubmittedContributionsCount = 0 })
+	}
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == (espsdk.Contribution{SubmissionBatchID: unchecked.ID}).Path() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return true
+		}
+		return false
+	}
+	client := srv.Client(espsdk.WithRetryPolicy(espsdk.NoRetries))
+
+	report, err := espsdk.DeleteBatches(context.Background(), client, espsdk.DeleteOptions{Match: espsdk.AnyBatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 1 || report.Deleted[0].ID != empty.ID || len(srv.Batches()) != 2 {
+		t.Errorf("got %+v", report)
+	}
+	if len(report.Skipped) != 2 || !strings.Contains(report.Skipped[0].Reason, "1 submitted") ||
+		!strings.Contains(report.Skipped[1].Reason, "could not be checked") {
+		t.Errorf("got skipped %+v", report.Skipped)
+	}
+}
@@ -0,0 +1,158 @@
+package espsdk
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A BatchSort is a field by which the results of a BatchQuery can be
+// ordered.
+type BatchSort string
+
+// The fields a BatchQuery can sort by.
+const (
+	SortByCreatedAt       BatchSort = "created_at"
+	SortByUpdatedAt       BatchSort = "updated_at"
+	SortByLastSubmittedAt BatchSort = "last_submitted_at"
+	SortBySubmissionName  BatchSort = "submission_name"
+)
+
+// A BatchQuery selects Batches for the account. Zero-valued fields do not
+// filter. ESP itself filters by Status and SubmissionType, so only matching
+// Batches are transferred; the other criteria are applied to the results.
+type BatchQuery struct {
+	Status         BatchStatus
+	SubmissionType string
+
+	// CreatedAfter, CreatedBefore, UpdatedAfter and UpdatedBefore bound
+	// CreatedAt and UpdatedAt, exclusively. A Batch without the timestamp
+	// does not match a bound on it.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	// Tags matches Batches that have every one of the tags.
+	Tags []string
+
+	// NameContains matches a substring of SubmissionName, ignoring case.
+	NameContains string
+
+	// IsGetty and IsIstock, when not nil, must equal the Batch's flag.
+	IsGetty  *bool
+	IsIstock *bool
+
+	// SortBy orders the results, ascending unless Descending is set.
+	// Without it, the results are in the order ESP returns them, newest
+	// first.
+	SortBy     BatchSort
+	Descending bool
+}
+
+// params returns the criteria ESP applies itself.
+func (q BatchQuery) params() url.Values {
+	params := url.Values{}
+	if q.Status != "" {
+		params.Set("status", string(q.Status))
+	}
+	if q.SubmissionType != "" {
+		params.Set("submission_type", q.SubmissionType)
+	}
+	return params
+}
+
+// Matches reports whether the Batch meets every criterion of the query.
+func (q BatchQuery) Matches(b Batch) bool {
+	switch {
+	case q.Status != "" && b.Status != q.Status,
+		q.SubmissionType != "" && b.SubmissionType != q.SubmissionType,
+		!after(b.CreatedAt, q.CreatedAfter),
+		!before(b.CreatedAt, q.CreatedBefore),
+		!after(b.UpdatedAt, q.UpdatedAfter),
+		!before(b.UpdatedAt, q.UpdatedBefore),
+		q.IsGetty != nil && b.IsGetty != *q.IsGetty,
+		q.IsIstock != nil && b.IsIstock != *q.IsIstock:
+		return false
+	}
+	if q.NameContains != "" &&
+		!strings.Contains(strings.ToLower(b.SubmissionName), strings.ToLower(q.NameContains)) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !hasTag(b.BatchTags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// Run requests every Batch matching the query, in the requested order.
+func (q BatchQuery) Run(ctx context.Context, client ContextClient) ([]Batch, error) {
+	it := NewBatchIterator(client)
+	it.params = q.params()
+	var batches []Batch
+	for it.Next(ctx) {
+		if q.Matches(it.Batch()) {
+			batches = append(batches, it.Batch())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	q.sort(batches)
+	return batches, nil
+}
+
+func (q BatchQuery) sort(batches []Batch) {
+	var less func(a, b Batch) bool
+	switch q.SortBy {
+	case SortByCreatedAt:
+		less = func(a, b Batch) bool { return earlier(a.CreatedAt, b.CreatedAt) }
+	case SortByUpdatedAt:
+		less = func(a, b Batch) bool { return earlier(a.UpdatedAt, b.UpdatedAt) }
+	case SortByLastSubmittedAt:
+		less = func(a, b Batch) bool { return earlier(a.LastSubmittedAt, b.LastSubmittedAt) }
+	case SortBySubmissionName:
+		less = func(a, b Batch) bool {
+			return strings.ToLower(a.SubmissionName) < strings.ToLower(b.SubmissionName)
+		}
+	default:
+		return
+	}
+	sort.SliceStable(batches, func(i, j int) bool {
+		if q.Descending {
+			return less(batches[j], batches[i])
+		}
+		return less(batches[i], batches[j])
+	})
+}
+
+// after reports whether t is after bound, or whether bound is unset.
+func after(t *time.Time, bound time.Time) bool {
+	return bound.IsZero() || t != nil && t.After(bound)
+}
+
+// before reports whether t is before bound, or whether bound is unset.
+func before(t *time.Time, bound time.Time) bool {
+	return bound.IsZero() || t != nil && t.Before(bound)
+}
+
+// earlier orders timestamps, with missing ones first.
+func earlier(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	return a.Before(*b)
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
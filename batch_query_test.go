@@ -0,0 +1,74 @@
+package espsdk_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+// pathRecorder records the paths of the GET requests sent through it.
+type pathRecorder struct {
+	espsdk.ContextClient
+	paths []string
+}
+
+func (r *pathRecorder) GetContext(ctx context.Context, object espsdk.Findable) (espsdk.Result, error) {
+	r.paths = append(r.paths, object.Path())
+	return r.ContextClient.GetContext(ctx, object)
+}
+
+func TestBatchQuery(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	day := func(d int) *time.Time {
+		t := time.Date(2016, 3, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	for _, b := range []espsdk.Batch{
+		{SubmissionName: "Spring Training", SubmissionType: "getty_editorial_still", CreatedAt: day(1), BatchTags: []string{"sports", "baseball"}, IsGetty: true},
+		{SubmissionName: "Opening Day", SubmissionType: "getty_editorial_still", CreatedAt: day(5), BatchTags: []string{"sports"}, IsGetty: true, Status: espsdk.BatchSubmitted},
+		{SubmissionName: "spring flowers", SubmissionType: "getty_creative_still", CreatedAt: day(3), BatchTags: []string{"nature"}, IsIstock: true},
+		{SubmissionName: "Training Camp", SubmissionType: "getty_editorial_still", CreatedAt: day(9), BatchTags: []string{"sports", "football"}, IsGetty: true},
+	} {
+		srv.AddBatch(b)
+	}
+	yes := true
+	names := func(batches []espsdk.Batch) string {
+		var n []string
+		for _, b := range batches {
+			n = append(n, b.SubmissionName)
+		}
+		return strings.Join(n, ", ")
+	}
+
+	cases := []struct {
+		query espsdk.BatchQuery
+		want  string
+	}{
+		{espsdk.BatchQuery{NameContains: "SPRING", SortBy: espsdk.SortBySubmissionName}, "spring flowers, Spring Training"},
+		{espsdk.BatchQuery{Tags: []string{"sports"}, SortBy: espsdk.SortByCreatedAt}, "Spring Training, Opening Day, Training Camp"},
+		{espsdk.BatchQuery{Tags: []string{"sports"}, SortBy: espsdk.SortByCreatedAt, Descending: true}, "Training Camp, Opening Day, Spring Training"},
+		{espsdk.BatchQuery{CreatedAfter: *day(2), CreatedBefore: *day(9), SortBy: espsdk.SortByCreatedAt}, "spring flowers, Opening Day"},
+		{espsdk.BatchQuery{IsIstock: &yes}, "spring flowers"},
+		{espsdk.BatchQuery{Status: espsdk.BatchOpen, SubmissionType: "getty_editorial_still", SortBy: espsdk.SortBySubmissionName}, "Spring Training, Training Camp"},
+	}
+	for _, tc := range cases {
+		batches, err := tc.query.Run(context.Background(), srv.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(batches); got != tc.want {
+			t.Errorf("%+v: got %q, want %q", tc.query, got, tc.want)
+		}
+	}
+
+	rec := &pathRecorder{ContextClient: srv.Client()}
+	espsdk.BatchQuery{Status: espsdk.BatchSubmitted, NameContains: "day"}.Run(context.Background(), rec)
+	if len(rec.paths) != 1 || !strings.Contains(rec.paths[0], "status=submitted") || strings.Contains(rec.paths[0], "day") {
+		t.Errorf("only the server-side criteria should be sent: %v", rec.paths)
+	}
+}
@@ -0,0 +1,177 @@
+package espsdk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// A BatchReport summarizes the progress of a set of Batches, combining the
+// counters ESP keeps for each Batch with the status of every Contribution
+// in it.
+type BatchReport struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Rows        []BatchReportRow `json:"batches"`
+}
+
+// NoContributionStatus is the status under which a BatchReport counts
+// Contributions without one. ESP statuses never contain parentheses, so it
+// cannot clash with a real status.
+const NoContributionStatus = "(none)"
+
+// A BatchReportRow is the part of a BatchReport about one Batch.
+// ContributionStatuses counts the Batch's Contributions by their status, or
+// NoContributionStatus.
+type BatchReportRow struct {
+	BatchID              string         `json:"batch_id"`
+	SubmissionName       string         `json:"submission_name"`
+	Status               BatchStatus    `json:"status"`
+	Contributions        int            `json:"contributions_count"`
+	Submitted            int            `json:"submitted_contributions_count"`
+	AwaitingReview       int            `json:"contributions_awaiting_review_count"`
+	Reviewed             int            `json:"reviewed_contributions_count"`
+	Revisable            int            `json:"revisable_contributions_count"`
+	ContributionStatuses map[string]int `json:"contribution_statuses"`
+}
+
+// A BatchReportBuilder collects the Batches to include in a BatchReport.
+//
+//	report, err := espsdk.NewBatchReportBuilder(client).Add(batches...).Build(ctx)
+//	if err != nil {
+//		...
+//	}
+//	report.WriteText(os.Stdout)
+type BatchReportBuilder struct {
+	client  ContextClient
+	batches []Batch
+}
+
+// NewBatchReportBuilder returns a BatchReportBuilder that requests the
+// Contributions of each Batch through the client.
+func NewBatchReportBuilder(client ContextClient) *BatchReportBuilder {
+	return &BatchReportBuilder{client: client}
+}
+
+// Add includes the Batches in the report, in the order given.
+func (rb *BatchReportBuilder) Add(batches ...Batch) *BatchReportBuilder {
+	rb.batches = append(rb.batches, batches...)
+	return rb
+}
+
+// Build requests the Contributions of every Batch and assembles the report.
+func (rb *BatchReportBuilder) Build(ctx context.Context) (*BatchReport, error) {
+	report := &BatchReport{GeneratedAt: time.Now()}
+	for _, b := range rb.batches {
+		row := BatchReportRow{
+			BatchID:              b.ID,
+			SubmissionName:       b.SubmissionName,
+			Status:               b.Status,
+			Contributions:        b.ContributionsCount,
+			Submitted:            b.SubmittedContributionsCount,
+			AwaitingReview:       b.ContributionsAwaitingReviewCount,
+			Reviewed:             b.ReviewedContributionsCount,
+			Revisable:            b.RevisableContributionsCount,
+			ContributionStatuses: make(map[string]int),
+		}
+		it := NewContributionIterator(rb.client, b.ID)
+		for it.Next(ctx) {
+			status := it.Contribution().Status
+			if status == "" {
+				status = NoContributionStatus
+			}
+			row.ContributionStatuses[status]++
+		}
+		if err := it.Err(); err != nil {
+			return nil, fmt.Errorf("espsdk: Contributions of Batch %s: %w", b.ID, err)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+// ContributionStatuses returns every Contribution status that occurs in the
+// report, sorted.
+func (r BatchReport) ContributionStatuses() []string {
+	seen := make(map[string]bool)
+	var statuses []string
+	for _, row := range r.Rows {
+		for status := range row.ContributionStatuses {
+			if !seen[status] {
+				seen[status] = true
+				statuses = append(statuses, status)
+			}
+		}
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// header returns the column names shared by the text and CSV renderings.
+func (r BatchReport) header() []string {
+	header := []string{"batch_id", "submission_name", "status", "contributions",
+		"submitted", "awaiting_review", "reviewed", "revisable"}
+	for _, status := range r.ContributionStatuses() {
+		header = append(header, "status_"+status)
+	}
+	return header
+}
+
+// records returns one row of cells per Batch, in the columns of header.
+func (r BatchReport) records() [][]string {
+	statuses := r.ContributionStatuses()
+	var records [][]string
+	for _, row := range r.Rows {
+		record := []string{row.BatchID, row.SubmissionName, string(row.Status),
+			strconv.Itoa(row.Contributions), strconv.Itoa(row.Submitted),
+			strconv.Itoa(row.AwaitingReview), strconv.Itoa(row.Reviewed),
+			strconv.Itoa(row.Revisable)}
+		for _, status := range statuses {
+			record = append(record, strconv.Itoa(row.ContributionStatuses[status]))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// WriteText renders the report as an aligned table.
+func (r BatchReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, record := range append([][]string{r.header()}, r.records()...) {
+		for i, cell := range record {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, cell)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON renders the report as indented JSON.
+func (r BatchReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV renders the report as CSV with a header row. There is a column
+// for each Contribution status in the report, named with a "status_" prefix
+// so that it cannot clash with the fixed columns; Contributions without a
+// status are counted under "status_" + NoContributionStatus.
+func (r BatchReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.header()); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(r.records()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
@@ -0,0 +1,64 @@
+package espsdk_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestBatchReport(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "Derby, day 1", SubmissionType: "getty_editorial_still"})
+	for _, status := range []string{"pending", "submitted", "submitted", "approved", "none"} {
+		srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Status: status})
+	}
+	unset := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID})
+	srv.UpdateContribution(b.ID, unset.ID, func(c *espsdk.Contribution) { c.Status = "" })
+	b, _ = srv.Batch(b.ID)
+
+	report, err := espsdk.NewBatchReportBuilder(srv.Client()).Add(b).Build(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := report.Rows[0]
+	if row.Contributions != 6 || row.AwaitingReview != 2 || row.ContributionStatuses["submitted"] != 2 ||
+		row.ContributionStatuses[espsdk.NoContributionStatus] != 1 || row.ContributionStatuses["none"] != 1 {
+		t.Errorf("got %+v", row)
+	}
+
+	var text, csv, js bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(text.String()), "\n"); len(lines) != 2 ||
+		!strings.Contains(lines[0], "approved") || !strings.HasPrefix(lines[1], b.ID+" ") {
+		t.Errorf("got\n%s", text.String())
+	}
+
+	if err := report.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	want := "batch_id,submission_name,status,contributions,submitted,awaiting_review,reviewed,revisable," +
+		"status_(none),status_approved,status_none,status_pending,status_submitted\n" +
+		b.ID + `,"Derby, day 1",open,6,5,2,3,0,1,1,1,1,2` + "\n"
+	if csv.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", csv.String(), want)
+	}
+
+	if err := report.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded espsdk.BatchReport
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if statuses := decoded.Rows[0].ContributionStatuses; statuses["approved"] != 1 || statuses[espsdk.NoContributionStatus] != 1 {
+		t.Errorf("got %s", js.String())
+	}
+}
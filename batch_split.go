@@ -0,0 +1,111 @@
+package espsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// A PlannedBatch is one of the Batches in a BatchPlan with the
+// Contributions that go into it. After BatchPlan.Create they are the
+// objects as saved.
+type PlannedBatch struct {
+	Batch         Batch
+	Contributions []Contribution
+}
+
+// A BatchPlan divides Contributions among as many Batches as needed to keep
+// each within a maximum size.
+type BatchPlan struct {
+	MaxBatchSize int
+	Batches      []PlannedBatch
+}
+
+// PlanBatches splits the Contributions into Batches of at most maxBatchSize
+// Contributions, in order, each a copy of the template. When more than one
+// Batch is needed they are named "<name> (1/3)", "<name> (2/3)" and so on.
+// The SubmissionType is checked by BatchPlan.Create.
+func PlanBatches(template Batch, contributions []Contribution, maxBatchSize int) (*BatchPlan, error) {
+	if maxBatchSize <= 0 {
+		return nil, fmt.Errorf("espsdk: invalid maximum batch size %d", maxBatchSize)
+	}
+	if !template.NameIsValid() {
+		return nil, ErrInvalidBatchName
+	}
+	plan := &BatchPlan{MaxBatchSize: maxBatchSize}
+	count := (len(contributions) + maxBatchSize - 1) / maxBatchSize
+	for i := 0; i < count; i++ {
+		end := (i + 1) * maxBatchSize
+		if end > len(contributions) {
+			end = len(contributions)
+		}
+		b := template
+		b.ID = ""
+		if count > 1 {
+			b.SubmissionName = fmt.Sprintf("%s (%d/%d)", template.SubmissionName, i+1, count)
+		}
+		plan.Batches = append(plan.Batches, PlannedBatch{
+			Batch:         b,
+			Contributions: append([]Contribution(nil), contributions[i*maxBatchSize:end]...),
+		})
+	}
+	return plan, nil
+}
+
+// Create creates each planned Batch and then its Contributions, replacing
+// them in the plan with the objects as saved. Every Batch is validated
+// against the types known to the client first, so that an invalid one
+// fails before any is created. If a request fails, Create stops and returns
+// the error; the Batches and Contributions created until then have IDs in
+// the plan.
+func (p *BatchPlan) Create(ctx context.Context, client ContextClient) error {
+	for _, planned := range p.Batches {
+		if err := planned.Batch.validateFor(client); err != nil {
+			return err
+		}
+	}
+	for i := range p.Batches {
+		planned := &p.Batches[i]
+		saved, err := planned.Batch.Create(ctx, client)
+		if err != nil {
+			return err
+		}
+		planned.Batch = *saved
+		for j, c := range planned.Contributions {
+			c.SubmissionBatchID = saved.ID
+			created, err := c.Create(ctx, client)
+			if err != nil {
+				return fmt.Errorf("espsdk: Contribution %d of Batch %q: %w", j+1, saved.SubmissionName, err)
+			}
+			planned.Contributions[j] = *created
+		}
+	}
+	return nil
+}
+
+// MaxBatchSize returns the largest number of Contributions the current user
+// may put in a Batch, according to the field restrictions.
+func (c Client) MaxBatchSize(ctx context.Context) (int, error) {
+	restrictions, err := c.GetFieldRestrictionsContext(ctx, FieldRestrictionQuery{})
+	if err != nil {
+		return 0, err
+	}
+	if restrictions.MaxBatchSize <= 0 {
+		return 0, errors.New("espsdk: ESP did not report a maximum batch size")
+	}
+	return restrictions.MaxBatchSize, nil
+}
+
+// CreateSplitBatches plans Batches for the Contributions within the current
+// user's MaxBatchSize, as PlanBatches does, and creates them.
+func (c Client) CreateSplitBatches(ctx context.Context, template Batch, contributions []Contribution) (*BatchPlan, error) {
+	size, err := c.MaxBatchSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := PlanBatches(template, contributions, size)
+	if err != nil {
+		return nil, err
+	}
+	return plan, plan.Create(ctx, c)
+}
@@ -0,0 +1,79 @@
+package espsdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestPlanBatches(t *testing.T) {
+	template := espsdk.Batch{SubmissionName: "Festival", SubmissionType: "getty_editorial_still"}
+	contributions := make([]espsdk.Contribution, 7)
+
+	plan, err := espsdk.PlanBatches(template, contributions, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range plan.Batches {
+		got = append(got, fmt.Sprintf("%s:%d", b.Batch.SubmissionName, len(b.Contributions)))
+	}
+	if want := "[Festival (1/3):3 Festival (2/3):3 Festival (3/3):1]"; fmt.Sprint(got) != want {
+		t.Errorf("got %v, want %s", got, want)
+	}
+
+	plan, _ = espsdk.PlanBatches(template, contributions[:3], 3)
+	if len(plan.Batches) != 1 || plan.Batches[0].Batch.SubmissionName != "Festival" {
+		t.Errorf("a single Batch should keep its name: %+v", plan.Batches)
+	}
+	if _, err := espsdk.PlanBatches(template, contributions, 0); err == nil {
+		t.Error("a zero size should be rejected")
+	}
+}
+
+func TestCreateSplitBatches(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	srv.FieldRestrictions.MaxBatchSize = 2
+	var contributions []espsdk.Contribution
+	for i := 0; i < 5; i++ {
+		contributions = append(contributions, espsdk.Contribution{Headline: fmt.Sprint("photo ", i)})
+	}
+
+	plan, err := srv.Client().CreateSplitBatches(context.Background(),
+		espsdk.Batch{SubmissionName: "Parade", SubmissionType: "getty_editorial_still"}, contributions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Batches) != 3 {
+		t.Fatalf("got %d batches", len(plan.Batches))
+	}
+	last := plan.Batches[2]
+	stored := srv.Contributions(last.Batch.ID)
+	if last.Batch.SubmissionName != "Parade (3/3)" || len(stored) != 1 || stored[0].Headline != "photo 4" ||
+		last.Contributions[0].ID != stored[0].ID {
+		t.Errorf("got %+v, stored %+v", last, stored)
+	}
+}
+
+func TestBatchPlanValidatesEveryBatchFirst(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	template := espsdk.Batch{SubmissionName: "Parade", SubmissionType: "getty_editorial_still"}
+	plan, err := espsdk.PlanBatches(template, make([]espsdk.Contribution, 3), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Batches[2].Batch.SubmissionType = "getty_unknown"
+
+	if err := plan.Create(context.Background(), srv.Client()); !errors.Is(err, espsdk.ErrInvalidBatchType) {
+		t.Errorf("got %v, want %v", err, espsdk.ErrInvalidBatchType)
+	}
+	if got := srv.Batches(); len(got) != 0 {
+		t.Errorf("no Batch should be created: %+v", got)
+	}
+}
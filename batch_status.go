@@ -0,0 +1,116 @@
+package espsdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// A BatchStatus is the stage of review a Batch has reached.
+type BatchStatus string
+
+// The states of a Batch known to the SDK. ESP does not document the
+// lifecycle of a Batch, so these and the transitions between them are the
+// SDK's expectation rather than a contract: a Batch is open while
+// Contributions are being added to it, is submitted when they have been sent
+// for review, moves to in_review once an editor picks it up and to reviewed
+// when every Contribution has been decided. A reviewed Batch with revisable
+// Contributions is reopened; one that is no longer needed is closed.
+//
+// ESP may report other statuses. The transition checks allow any move to or
+// from a status the SDK does not know, so that they never reject a Batch
+// in a real state.
+const (
+	BatchOpen      BatchStatus = "open"
+	BatchSubmitted BatchStatus = "submitted"
+	BatchInReview  BatchStatus = "in_review"
+	BatchReviewed  BatchStatus = "reviewed"
+	BatchClosed    BatchStatus = "closed"
+)
+
+// batchTransitions lists the states each state can move to directly.
+var batchTransitions = map[BatchStatus][]BatchStatus{
+	BatchOpen:      {BatchSubmitted, BatchClosed},
+	BatchSubmitted: {BatchOpen, BatchInReview},
+	BatchInReview:  {BatchReviewed},
+	BatchReviewed:  {BatchOpen, BatchClosed},
+	BatchClosed:    nil,
+}
+
+// Known reports whether s is one of the states defined by the SDK.
+func (s BatchStatus) Known() bool {
+	_, ok := batchTransitions[s]
+	return ok
+}
+
+// Final reports whether a Batch in state s can no longer change.
+func (s BatchStatus) Final() bool {
+	return s.Known() && len(batchTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether a Batch can move from s to next in a
+// single step. It is true if either state is unknown.
+func (s BatchStatus) CanTransitionTo(next BatchStatus) bool {
+	if !s.Known() || !next.Known() {
+		return true
+	}
+	for _, t := range batchTransitions[s] {
+		if t == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanReach reports whether a Batch in state s can eventually reach target.
+// It is true if either state is unknown.
+func (s BatchStatus) CanReach(target BatchStatus) bool {
+	if s == target || !s.Known() || !target.Known() {
+		return true
+	}
+	seen := map[BatchStatus]bool{s: true}
+	queue := []BatchStatus{s}
+	for len(queue) > 0 {
+		for _, next := range batchTransitions[queue[0]] {
+			if next == target {
+				return true
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+		queue = queue[1:]
+	}
+	return false
+}
+
+// DefaultPollInterval is how often WaitForBatchStatus polls when it is given
+// no interval.
+const DefaultPollInterval = 10 * time.Second
+
+// WaitForBatchStatus polls the Batch every interval until its status is
+// target, and returns the Batch as last fetched. It gives up when ctx is done,
+// so use context.WithTimeout or context.WithDeadline to bound the wait, or
+// when the Batch reaches a known state from which target cannot be reached,
+// as CanReach reports. The target may be a status the SDK does not know.
+func WaitForBatchStatus(ctx context.Context, client ContextClient, batchID string, target BatchStatus, interval time.Duration) (*Batch, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	for {
+		batch, err := Batch{ID: batchID}.Get(ctx, client)
+		if err != nil {
+			return batch, err
+		}
+		if batch.Status == target {
+			return batch, nil
+		}
+		if !batch.Status.CanReach(target) {
+			return batch, fmt.Errorf("espsdk: Batch %s is %s and cannot become %s", batchID, batch.Status, target)
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return batch, err
+		}
+	}
+}
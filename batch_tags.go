@@ -0,0 +1,149 @@
+package espsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrBatchModified is returned by AddTags and RemoveTags when the Batch on
+// the server was updated after the caller's copy was fetched.
+var ErrBatchModified = errors.New("espsdk: the Batch was modified since it was fetched")
+
+// AddTags adds tags to the Batch, keeping those already on it, including
+// tags added by others since the receiver was fetched. It returns the Batch
+// as saved.
+//
+// The tags are merged into a freshly fetched copy of the Batch, which is
+// sent back with its UpdatedAt. If ESP rejects the save with 409 Conflict
+// or 412 Precondition Failed because the Batch changed in between, or the
+// Batch fetched after the save is missing the tags because of a later save,
+// the merge is retried, up to tagAttempts times before ErrBatchModified is
+// returned.
+//
+// ESP does not document that it rejects a save made from an outdated copy.
+// Where it does not, a tag that another client adds between the fetch and
+// the save is overwritten, and AddTags cannot tell.
+//
+// If the receiver's UpdatedAt is set, it is also compared with the saved
+// Batch first and ErrBatchModified is returned if they differ, so that a
+// caller acting on a stale copy can re-fetch and decide again.
+func (b Batch) AddTags(ctx context.Context, client ContextClient, tags ...string) (*Batch, error) {
+	return b.editTags(ctx, client, "Batch.AddTags", func(current []string) []string {
+		return mergeTags(current, tags)
+	})
+}
+
+// RemoveTags removes tags from the Batch, leaving any others in place, and
+// returns the Batch as saved. It guards against concurrent changes in the
+// same way as AddTags.
+func (b Batch) RemoveTags(ctx context.Context, client ContextClient, tags ...string) (*Batch, error) {
+	return b.editTags(ctx, client, "Batch.RemoveTags", func(current []string) []string {
+		var kept []string
+		for _, t := range current {
+			if !hasTag(tags, t) {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	})
+}
+
+// tagAttempts is how many times AddTags and RemoveTags save the tags of a
+// Batch that keeps changing before giving up.
+const tagAttempts = 3
+
+func (b Batch) editTags(ctx context.Context, client ContextClient, desc string, edit func([]string) []string) (*Batch, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := b.Get(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		if attempt == 1 && b.UpdatedAt != nil && (current.UpdatedAt == nil || !current.UpdatedAt.Equal(*b.UpdatedAt)) {
+			return current, fmt.Errorf("%w: Batch %s was updated at %v", ErrBatchModified, b.ID, current.UpdatedAt)
+		}
+		tags := edit(current.BatchTags)
+		if sameTags(tags, current.BatchTags) {
+			return current, nil
+		}
+
+		update := batchTagsUpdate{ID: b.ID, Tags: tags, UpdatedAt: current.UpdatedAt}
+		result, err := client.PutContext(ctx, update, b.Path())
+		err = checkResponse(result, err)
+		if err != nil && !isConflict(err) {
+			result.Log().Error(desc)
+			return nil, err
+		}
+		if err == nil {
+			result.Log().Info(desc)
+			saved, err := b.Get(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+			if sameTags(edit(saved.BatchTags), saved.BatchTags) {
+				return saved, nil
+			}
+		}
+		if attempt >= tagAttempts {
+			return nil, fmt.Errorf("%w: Batch %s kept changing while its tags were saved", ErrBatchModified, b.ID)
+		}
+		result.Log().WithField("attempt", attempt).Warn(desc)
+	}
+}
+
+// isConflict reports whether err is ESP rejecting a save because the
+// object changed since it was fetched.
+func isConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict) || hasStatus(err, http.StatusPreconditionFailed)
+}
+
+// A batchTagsUpdate changes only the tags of a Batch. Unlike a BatchUpdate,
+// it sends an empty list rather than omitting it, so that the last tag can
+// be removed. UpdatedAt, if set, is that of the copy the tags were edited
+// from.
+type batchTagsUpdate struct {
+	ID        string
+	Tags      []string
+	UpdatedAt *time.Time
+}
+
+func (u batchTagsUpdate) Path() string { return Batch{ID: u.ID}.Path() }
+
+func (u batchTagsUpdate) Marshal() ([]byte, error) {
+	tags := u.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	batch := map[string]interface{}{"batch_tags": tags}
+	if u.UpdatedAt != nil {
+		batch["updated_at"] = u.UpdatedAt
+	}
+	return json.Marshal(map[string]interface{}{"submission_batch": batch})
+}
+
+// mergeTags appends the tags that are not already present, keeping the
+// existing order.
+func mergeTags(current, tags []string) []string {
+	merged := append([]string(nil), current...)
+	for _, t := range tags {
+		if t != "" && !hasTag(merged, t) {
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
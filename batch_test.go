@@ -0,0 +1,214 @@
+package espsdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestBatchCRUD(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	created, err := espsdk.Batch{
+		SubmissionName: "Re-shoot",
+		SubmissionType: "getty_editorial_still",
+		BatchTags:      []string{"sports"},
+	}.Create(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.Status == "" {
+		t.Fatalf("the created Batch should carry server-assigned fields: %+v", created)
+	}
+
+	created.Note = "second day"
+	updated, err := created.Update(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Note != "second day" {
+		t.Errorf("got %+v", updated)
+	}
+
+	fetched, err := espsdk.Batch{ID: created.ID}.Get(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Note != "second day" || fetched.SubmissionName != "Re-shoot" {
+		t.Errorf("got %+v", fetched)
+	}
+
+	if err := fetched.Delete(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetched.Get(ctx, client); !espsdk.IsNotFound(err) {
+		t.Errorf("got %v, want not found", err)
+	}
+}
+
+func TestBatchValidationHappensBeforeRequests(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	_, err := espsdk.Batch{SubmissionType: "getty_creative_still"}.Create(ctx, client)
+	if err != espsdk.ErrInvalidBatchName {
+		t.Errorf("got %v, want %v", err, espsdk.ErrInvalidBatchName)
+	}
+	_, err = espsdk.Batch{ID: "1", SubmissionName: "x", SubmissionType: "istock_creative_still"}.Update(ctx, client)
+	if !errors.Is(err, espsdk.ErrInvalidBatchType) {
+		t.Errorf("got %v, want %v", err, espsdk.ErrInvalidBatchType)
+	}
+	if err := (espsdk.Batch{}).Delete(ctx, client); err != espsdk.ErrMissingID {
+		t.Errorf("got %v, want %v", err, espsdk.ErrMissingID)
+	}
+	if srv.TokenRequests() != 0 {
+		t.Errorf("no request should have been sent")
+	}
+}
+
+func TestBatchStatusTransitions(t *testing.T) {
+	cases := []struct {
+		from, to             espsdk.BatchStatus
+		directly, eventually bool
+	}{
+		{espsdk.BatchOpen, espsdk.BatchSubmitted, true, true},
+		{espsdk.BatchOpen, espsdk.BatchReviewed, false, true},
+		{espsdk.BatchReviewed, espsdk.BatchOpen, true, true},
+		{espsdk.BatchClosed, espsdk.BatchOpen, false, false},
+		{espsdk.BatchInReview, espsdk.BatchSubmitted, false, true},
+		{"archived", espsdk.BatchReviewed, true, true},
+		{espsdk.BatchOpen, "archived", true, true},
+		{espsdk.BatchClosed, "archived", true, true},
+	}
+	for _, tc := range cases {
+		if got := tc.from.CanTransitionTo(tc.to); got != tc.directly {
+			t.Errorf("%s.CanTransitionTo(%s) = %v", tc.from, tc.to, got)
+		}
+		if got := tc.from.CanReach(tc.to); got != tc.eventually {
+			t.Errorf("%s.CanReach(%s) = %v", tc.from, tc.to, got)
+		}
+	}
+	if !espsdk.BatchClosed.Final() || espsdk.BatchReviewed.Final() {
+		t.Error("only closed should be final")
+	}
+}
+
+func TestWaitForBatchStatus(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_video", Status: espsdk.BatchInReview})
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.Status = espsdk.BatchReviewed })
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got, err := espsdk.WaitForBatchStatus(ctx, client, b.ID, espsdk.BatchReviewed, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != espsdk.BatchReviewed {
+		t.Errorf("got %s", got.Status)
+	}
+
+	srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.Status = espsdk.BatchClosed })
+	if _, err := espsdk.WaitForBatchStatus(ctx, client, b.ID, espsdk.BatchReviewed, time.Millisecond); err == nil {
+		t.Error("a closed Batch should never become reviewed")
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.Status = "archived" })
+	}()
+	if got, err = espsdk.WaitForBatchStatus(ctx, client, b.ID, "archived", 10*time.Millisecond); err != nil {
+		t.Fatalf("waiting for a status unknown to the SDK: %v", err)
+	}
+	if got.Status != "archived" {
+		t.Errorf("got %s", got.Status)
+	}
+
+	srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.Status = espsdk.BatchOpen })
+	short, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := espsdk.WaitForBatchStatus(short, client, b.ID, espsdk.BatchReviewed, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want deadline exceeded", err)
+	}
+}
+
+func TestAddTagsRetriesConcurrentSaves(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	srv.RejectStaleUpdates = true
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still", BatchTags: []string{"a"}})
+
+	var puts, gets int
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		switch r.Method {
+		case "PUT":
+			puts++
+			if puts == 1 {
+				// Another client saves between our fetch and our save.
+				srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.BatchTags = append(b.BatchTags, "theirs") })
+			}
+		case "GET":
+			gets++
+			if puts == 2 && gets == 3 {
+				// Another client saves right after us, from an older copy.
+				srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.BatchTags = []string{"a", "theirs", "other"} })
+			}
+		}
+		return false
+	}
+
+	saved, err := espsdk.Batch{ID: b.ID}.AddTags(context.Background(), srv.Client(), "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(saved.BatchTags, ","); got != "a,theirs,other,mine" || puts != 3 {
+		t.Errorf("got %s after %d saves", got, puts)
+	}
+}
+
+func TestBatchTags(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still", BatchTags: []string{"a"}})
+
+	// A teammate adds a tag after our copy was fetched without a timestamp.
+	srv.UpdateBatch(b.ID, func(b *espsdk.Batch) { b.BatchTags = append(b.BatchTags, "theirs") })
+	updated, err := espsdk.Batch{ID: b.ID}.AddTags(ctx, client, "mine", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(updated.BatchTags, ","); got != "a,theirs,mine" {
+		t.Errorf("got %s", got)
+	}
+
+	stale := b
+	if _, err := stale.AddTags(ctx, client, "late"); !errors.Is(err, espsdk.ErrBatchModified) {
+		t.Errorf("got %v, want %v", err, espsdk.ErrBatchModified)
+	}
+
+	updated, err = updated.RemoveTags(ctx, client, "a", "theirs", "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := srv.Batch(b.ID); len(updated.BatchTags) != 0 || len(saved.BatchTags) != 0 {
+		t.Errorf("every tag should have been removed: %v", saved.BatchTags)
+	}
+}
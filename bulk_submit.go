@@ -0,0 +1,110 @@
+package espsdk
+
+import (
+	"context"
+	"sync"
+)
+
+// A SubmitOutcome is what happened to one Contribution in a bulk submit.
+type SubmitOutcome string
+
+// The outcomes of a bulk submit.
+const (
+	Submitted SubmitOutcome = "submitted"
+	Skipped   SubmitOutcome = "skipped"
+	Failed    SubmitOutcome = "failed"
+)
+
+// A SubmitResult reports the outcome for one Contribution. After a
+// successful submit, Contribution is the Contribution as saved. Err is set
+// only when the outcome is Failed.
+type SubmitResult struct {
+	Contribution Contribution
+	Outcome      SubmitOutcome
+	Err          error
+}
+
+// BulkSubmitResults are the results of a bulk submit, in the order of the
+// Contributions.
+type BulkSubmitResults []SubmitResult
+
+// Count returns the number of results with the outcome.
+func (rs BulkSubmitResults) Count(outcome SubmitOutcome) int {
+	n := 0
+	for _, r := range rs {
+		if r.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
+
+// Failures returns the results of the Contributions that failed.
+func (rs BulkSubmitResults) Failures() BulkSubmitResults {
+	var failed BulkSubmitResults
+	for _, r := range rs {
+		if r.Outcome == Failed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// DefaultSubmitWorkers is the number of concurrent submits BulkSubmit uses
+// when it is given no number.
+const DefaultSubmitWorkers = 4
+
+// BulkSubmit submits the Submittable Contributions using up to workers
+// concurrent requests and skips the rest. A failed submit does not stop the
+// others; once ctx is done, the Contributions not yet submitted fail with
+// its error.
+func BulkSubmit(ctx context.Context, client ContextClient, contributions ContributionList, workers int) BulkSubmitResults {
+	if workers <= 0 {
+		workers = DefaultSubmitWorkers
+	}
+	results := make(BulkSubmitResults, len(contributions))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = submitOne(ctx, client, contributions[i])
+			}
+		}()
+	}
+	for i := range contributions {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func submitOne(ctx context.Context, client ContextClient, c Contribution) SubmitResult {
+	if !c.Submittable {
+		return SubmitResult{Contribution: c, Outcome: Skipped}
+	}
+	if err := ctx.Err(); err != nil {
+		return SubmitResult{Contribution: c, Outcome: Failed, Err: err}
+	}
+	result, err := c.SubmitContext(ctx, client)
+	if err != nil {
+		return SubmitResult{Contribution: c, Outcome: Failed, Err: err}
+	}
+	if saved, err := (Contribution{}).Unmarshal(result.Payload); err == nil && saved != nil {
+		c = *saved
+	}
+	return SubmitResult{Contribution: c, Outcome: Submitted}
+}
+
+// BulkSubmitBatch requests every Contribution in the Batch and submits them
+// as BulkSubmit does.
+func BulkSubmitBatch(ctx context.Context, client ContextClient, batchID string, workers int) (BulkSubmitResults, error) {
+	contributions, err := NewContributionIterator(client, batchID).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return BulkSubmit(ctx, client, contributions, workers), nil
+}
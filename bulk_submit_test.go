@@ -0,0 +1,77 @@
+package espsdk_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+// concurrencyRecorder records the most PUT requests in flight at once.
+type concurrencyRecorder struct {
+	espsdk.ContextClient
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (r *concurrencyRecorder) PutContext(ctx context.Context, object espsdk.RESTObject, path string) (espsdk.Result, error) {
+	r.mu.Lock()
+	r.inFlight++
+	if r.inFlight > r.max {
+		r.max = r.inFlight
+	}
+	r.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+	return r.ContextClient.PutContext(ctx, object, path)
+}
+
+func TestBulkSubmitBatch(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still"})
+	for i := 0; i < 10; i++ {
+		srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Headline: fmt.Sprint(i), FileName: "a.jpg"})
+	}
+	unready := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID})
+
+	client := &concurrencyRecorder{ContextClient: srv.Client()}
+	results, err := espsdk.BulkSubmitBatch(context.Background(), client, b.ID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Count(espsdk.Submitted) != 10 || results.Count(espsdk.Skipped) != 1 || len(results.Failures()) != 0 {
+		t.Errorf("got %+v", results)
+	}
+	if last := results[len(results)-1]; last.Contribution.ID != unready.ID || last.Outcome != espsdk.Skipped {
+		t.Errorf("results should be in input order: %+v", last)
+	}
+	if results[0].Contribution.Status != "submitted" {
+		t.Errorf("a submitted result should hold the saved Contribution: %+v", results[0].Contribution)
+	}
+	if client.max > 3 {
+		t.Errorf("%d submits ran at once, want at most 3", client.max)
+	}
+}
+
+func TestBulkSubmitReportsFailures(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still"})
+	ok := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Headline: "ok", FileName: "a.jpg"})
+	gone := espsdk.Contribution{SubmissionBatchID: b.ID, ID: "404", Submittable: true}
+
+	results := espsdk.BulkSubmit(context.Background(), srv.Client(), espsdk.ContributionList{ok, gone}, 0)
+	if results[0].Outcome != espsdk.Submitted || results[1].Outcome != espsdk.Failed || !espsdk.IsNotFound(results[1].Err) {
+		t.Errorf("got %+v", results)
+	}
+}
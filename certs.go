@@ -1,5 +1,7 @@
 package espsdk
 
+// pemCerts is the bundle of root certificates behind BundledCertificates
+// and TLSOptions.UseBundledRoots.
 var pemCerts = []byte(`
 -----BEGIN CERTIFICATE-----
 MIIDzzCCAregAwIBAgIDAWweMA0GCSqGSIb3DQEBBQUAMIGNMQswCQYDVQQGEwJB
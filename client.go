@@ -1,8 +1,11 @@
 package espsdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"sync"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/dysolution/sleepwalker"
@@ -11,32 +14,65 @@ import (
 // A Client communicates with the ESP REST API.
 type Client struct {
 	*sleepwalker.Client
-}
 
-// A Result provides information about the response from the ESP REST API.
-type Result struct {
-	sleepwalker.Result
+	// HTTPClient sends the Client's requests. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+
+	// OAuthEndpoint is where the Client obtains its tokens. GetClient sets
+	// it to the package-level OAuthEndpoint; change it to authenticate
+	// against another server, such as an esptest.Server.
+	OAuthEndpoint string
+
+	// RetryPolicy governs how the Client's requests recover from
+	// throttling and transient failures. GetClient sets it to
+	// DefaultRetryPolicy.
+	RetryPolicy RetryPolicy
+
+	// TokenStore holds the OAuth token used by the Client's requests.
+	// GetClient uses a MemoryTokenStore; a FileTokenStore lets separate
+	// processes reuse one token.
+	TokenStore TokenStore
+
+	// UserAgent is sent with every request.
+	UserAgent string
+
+	// RateLimiter, if not nil, holds back the Client's requests so that
+	// they stay within ESP's limits.
+	RateLimiter *RateLimiter
+
+	credentials sleepwalker.Credentials
+	apiRoot     string
+	logger      *logrus.Logger
+	tokenMu     *sync.Mutex
+	knownTypes  *submissionTypeSet
 }
 
 // GetClient provides a client for communicating with the ESP REST API.
+// NewClient accepts further settings.
 func GetClient(key, secret, username, password, apiRoot string, log *logrus.Logger) Client {
-	config := &sleepwalker.Config{
-		Credentials: &sleepwalker.Credentials{
-			APIKey:    key,
-			APISecret: secret,
-			Username:  username,
-			Password:  password,
-		},
-		OAuthEndpoint: OAuthEndpoint,
-		APIRoot:       apiRoot,
-		Logger:        log,
+	client, err := newClient(
+		WithCredentials(key, secret, username, password),
+		WithAPIRoot(apiRoot),
+		WithLogger(log),
+	)
+	if err != nil {
+		// None of the options above can fail; this is unreachable.
+		panic(err)
 	}
-	return Client{sleepwalker.GetClient(config)}
+	return client
 }
 
 // ValidateKeywords queries the ESP keywords endpoint and reports whether each
 // provided keyword is valid.
-func (c Client) ValidateKeywords(keywords []string, mediaType string) []Keyword {
+func (c Client) ValidateKeywords(keywords []string, mediaType string) ([]Keyword, error) {
+	return c.ValidateKeywordsContext(context.Background(), keywords, mediaType)
+}
+
+// ValidateKeywordsContext is like ValidateKeywords but sends the request
+// with the provided context.
+func (c Client) ValidateKeywordsContext(ctx context.Context, keywords []string, mediaType string) ([]Keyword, error) {
+	desc := "Client.ValidateKeywords"
 	reqPayload := struct {
 		Keywords  []string `json:"keywords"`
 		MediaType string   `json:"media_type"`
@@ -45,11 +81,19 @@ func (c Client) ValidateKeywords(keywords []string, mediaType string) []Keyword
 		MediaType: mediaType,
 	}
 
-	bytes, _ := json.Marshal(reqPayload)
-	result, _ := c.GetWithPayload(Endpoints.Keywords, bytes)
+	bytes, err := json.Marshal(reqPayload)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.GetWithPayloadContext(ctx, Endpoints.Keywords, bytes)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return nil, err
+	}
 	var payload map[string]map[string][]interface{}
 	if err := json.Unmarshal(result.Payload, &payload); err != nil {
 		Log.Error(err)
+		return nil, err
 	}
 
 	out, _ := json.MarshalIndent(payload, "", "  ")
@@ -67,44 +111,69 @@ func (c Client) ValidateKeywords(keywords []string, mediaType string) []Keyword
 			outKW = append(outKW, Keyword{Term: inKW, Valid: false})
 		}
 	}
-	return outKW
+	result.Log().Info(desc)
+	return outKW, nil
 }
 
 // GetControlledValues returns complete lists of values and descriptions for
 // fields with controlled vocabularies, grouped by submission type.
-func (c Client) GetControlledValues() ControlledValues {
+func (c Client) GetControlledValues() (ControlledValues, error) {
+	return c.GetControlledValuesContext(context.Background())
+}
+
+// GetControlledValuesContext is like GetControlledValues but sends the
+// request with the provided context.
+func (c Client) GetControlledValuesContext(ctx context.Context) (ControlledValues, error) {
 	desc := "Client.GetControlledValues"
-	result, err := c.GetPath(Endpoints.ControlledValues)
-	if err != nil {
-		return ControlledValues{}
+	result, err := c.GetPathContext(ctx, Endpoints.ControlledValues)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return ControlledValues{}, err
 	}
 	result.Log().Info(desc)
-	return parseCV(result)
+	return parseCV(result.Payload), nil
 }
 
 // GetTranscoderMappings lists acceptable transcoder mapping values
 // for Getty and iStock video.
-func (c Client) GetTranscoderMappings() *TranscoderMappingList {
+func (c Client) GetTranscoderMappings() (*TranscoderMappingList, error) {
+	return c.GetTranscoderMappingsContext(context.Background())
+}
+
+// GetTranscoderMappingsContext is like GetTranscoderMappings but sends the
+// request with the provided context.
+func (c Client) GetTranscoderMappingsContext(ctx context.Context) (*TranscoderMappingList, error) {
 	desc := "Client.GetTranscoderMappings"
-	result, err := c.GetPath(Endpoints.TranscoderMappings)
-	if err != nil {
-		return &TranscoderMappingList{}
+	result, err := c.GetPathContext(ctx, Endpoints.TranscoderMappings)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return &TranscoderMappingList{}, err
 	}
 	if result.Payload == nil {
-		return &TranscoderMappingList{}
+		return &TranscoderMappingList{}, errors.New("empty payload")
 	}
 	result.Log().Info(desc)
-	return TranscoderMappingList{}.Unmarshal(result.Payload)
+	return TranscoderMappingList{}.Unmarshal(result.Payload), nil
 }
 
 // GetEvents returns a list of events that match the provided criteria.
 func (c Client) GetEvents(params EventQuery) (*EventResponse, error) {
+	return c.GetEventsContext(context.Background(), params)
+}
+
+// GetEventsContext is like GetEvents but sends the request with the
+// provided context.
+func (c Client) GetEventsContext(ctx context.Context, params EventQuery) (*EventResponse, error) {
 	desc := "Client.GetEvents"
-	bytes, _ := json.Marshal(params)
-	result, err := c.GetWithPayload(Endpoints.Events, bytes)
+	bytes, err := json.Marshal(params)
 	if err != nil {
 		return &EventResponse{}, err
 	}
+	result, err := c.GetWithPayloadContext(ctx, Endpoints.Events, bytes)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return &EventResponse{}, err
+	}
 	if result.Payload == nil {
 		return &EventResponse{}, errors.New("empty payload")
 	}
@@ -115,12 +184,22 @@ func (c Client) GetEvents(params EventQuery) (*EventResponse, error) {
 // GetFieldRestrictions returns a list of field restrictions that match the
 // provided criteria.
 func (c Client) GetFieldRestrictions(params FieldRestrictionQuery) (*FieldRestrictionResponse, error) {
+	return c.GetFieldRestrictionsContext(context.Background(), params)
+}
+
+// GetFieldRestrictionsContext is like GetFieldRestrictions but sends the
+// request with the provided context.
+func (c Client) GetFieldRestrictionsContext(ctx context.Context, params FieldRestrictionQuery) (*FieldRestrictionResponse, error) {
 	desc := "Client.GetFieldRestrictions"
-	bytes, _ := json.Marshal(params)
-	result, err := c.GetWithPayload(Endpoints.FieldRestrictions, bytes)
+	bytes, err := json.Marshal(params)
 	if err != nil {
 		return &FieldRestrictionResponse{}, err
 	}
+	result, err := c.GetWithPayloadContext(ctx, Endpoints.FieldRestrictions, bytes)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return &FieldRestrictionResponse{}, err
+	}
 	if result.Payload == nil {
 		return &FieldRestrictionResponse{}, errors.New("empty payload")
 	}
@@ -129,47 +208,71 @@ func (c Client) GetFieldRestrictions(params FieldRestrictionQuery) (*FieldRestri
 }
 
 // GetTermList lists all possible values for the given controlled vocabulary.
-func (c Client) GetTermList(endpoint string) *TermList {
+func (c Client) GetTermList(endpoint string) (*TermList, error) {
+	return c.GetTermListContext(context.Background(), endpoint)
+}
+
+// GetTermListContext is like GetTermList but sends the request with the
+// provided context.
+func (c Client) GetTermListContext(ctx context.Context, endpoint string) (*TermList, error) {
 	desc := "Client.GetTermList"
-	result, err := c.GetPath(endpoint)
-	if err != nil {
-		return &TermList{}
+	result, err := c.GetPathContext(ctx, endpoint)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return &TermList{}, err
 	}
 	if result.Payload == nil {
-		return &TermList{}
+		return &TermList{}, errors.New("empty payload")
 	}
 	result.Log().Info(desc)
-	return TermList{}.Unmarshal(result.Payload)
+	return TermList{}.Unmarshal(result.Payload), nil
 }
 
 // GetTermIntList lists all possible values for the given controlled vocabulary.
-func (c Client) GetTermIntList(endpoint string) *TermIntList {
+func (c Client) GetTermIntList(endpoint string) (*TermIntList, error) {
+	return c.GetTermIntListContext(context.Background(), endpoint)
+}
+
+// GetTermIntListContext is like GetTermIntList but sends the request with
+// the provided context.
+func (c Client) GetTermIntListContext(ctx context.Context, endpoint string) (*TermIntList, error) {
 	desc := "Client.GetTermListInt"
-	result, err := c.GetPath(endpoint)
-	if err != nil {
-		return &TermIntList{}
+	result, err := c.GetPathContext(ctx, endpoint)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return &TermIntList{}, err
 	}
 	if result.Payload == nil {
-		return &TermIntList{}
+		return &TermIntList{}, errors.New("empty payload")
 	}
 	result.Log().Info(desc)
-	return TermIntList{}.Unmarshal(result.Payload)
-}
-
-// DeleteLastBatch looks up the newest Batch and deletes it.
-func DeleteLastBatch(c sleepwalker.RESTClient) (sleepwalker.Result, error) {
-	lastBatch := Batch{}.Index(c).Last()
-	return c.Delete(lastBatch)
+	return TermIntList{}.Unmarshal(result.Payload), nil
 }
 
 // SubmitLastPhoto subtmits the newest Contribution for review and publication.
 func SubmitLastPhoto(c sleepwalker.RESTClient) (sleepwalker.Result, error) {
-	newestBatch := Batch{}.Index(c).Last()
-	newestContribution, err := Contribution{
-		SubmissionBatchID: newestBatch.ID,
-	}.Index(c, newestBatch.ID).Last()
+	result, err := SubmitLastPhotoContext(context.Background(), contextClient(c))
+	return result.Result, err
+}
+
+// SubmitLastPhotoContext is like SubmitLastPhoto but sends its requests with
+// the provided context.
+func SubmitLastPhotoContext(ctx context.Context, c ContextClient) (Result, error) {
+	batches, err := Batch{}.IndexContext(ctx, c)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(batches.Items) == 0 {
+		return Result{}, errors.New("no batches")
+	}
+	newestBatch := batches.Last()
+	contributions, err := Contribution{}.IndexContext(ctx, c, newestBatch.ID)
+	if err != nil {
+		return Result{}, err
+	}
+	newestContribution, err := contributions.Last()
 	if err != nil {
-		return sleepwalker.Result{}, err
+		return Result{}, err
 	}
-	return c.Put(newestContribution, newestContribution.Path()+"/submit")
+	return newestContribution.SubmitContext(ctx, c)
 }
@@ -0,0 +1,209 @@
+package espsdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// A Profile holds the settings for one ESP account. Environment names a
+// registered Environment; APIRoot and OAuthEndpoint, if set, override it.
+type Profile struct {
+	Environment   string `json:"environment" toml:"environment"`
+	APIRoot       string `json:"api_root" toml:"api_root"`
+	OAuthEndpoint string `json:"oauth_endpoint" toml:"oauth_endpoint"`
+	APIKey        string `json:"api_key" toml:"api_key"`
+	APISecret     string `json:"api_secret" toml:"api_secret"`
+	Username      string `json:"username" toml:"username"`
+	Password      string `json:"password" toml:"password"`
+	TokenFile     string `json:"token_file" toml:"token_file"`
+}
+
+// A Config is the contents of a config file: any number of account
+// profiles and the custom Environments they may refer to.
+//
+// In TOML:
+//
+//	default_profile = "sandbox"
+//
+//	[environments.staging]
+//	api_root = "https://esp-staging.example.com/esp"
+//	oauth_endpoint = "https://esp-staging.example.com/oauth2/token"
+//
+//	[profiles.sandbox]
+//	environment = "sandbox"
+//	api_key = "..."
+//	api_secret = "..."
+//	username = "..."
+//	password = "..."
+//
+// JSON files use the same keys.
+type Config struct {
+	DefaultProfile string                 `json:"default_profile" toml:"default_profile"`
+	Environments   map[string]Environment `json:"environments" toml:"environments"`
+	Profiles       map[string]Profile     `json:"profiles" toml:"profiles"`
+}
+
+// The environment variables read by ProfileFromEnv and NewClientFromConfig.
+const (
+	EnvConfig        = "ESP_CONFIG"
+	EnvProfile       = "ESP_PROFILE"
+	EnvEnvironment   = "ESP_ENVIRONMENT"
+	EnvAPIRoot       = "ESP_API_ROOT"
+	EnvOAuthEndpoint = "ESP_OAUTH_ENDPOINT"
+	EnvAPIKey        = "ESP_API_KEY"
+	EnvAPISecret     = "ESP_API_SECRET"
+	EnvUsername      = "ESP_USERNAME"
+	EnvPassword      = "ESP_PASSWORD"
+	EnvTokenFile     = "ESP_TOKEN_FILE"
+)
+
+// LoadConfig reads a config file. Files ending in .toml are parsed as TOML
+// and all others as JSON.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		if _, err := toml.DecodeFile(path, &config); err != nil {
+			return Config{}, err
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return Config{}, err
+		}
+	}
+	for name, env := range config.Environments {
+		if env.Name == "" {
+			env.Name = name
+			config.Environments[name] = env
+		}
+	}
+	return config, nil
+}
+
+// Profile returns the named profile, or the default profile if name is
+// empty, with its Environment resolved into APIRoot and OAuthEndpoint.
+// Environments defined in the Config take precedence over registered ones.
+func (c Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		name = "default"
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("espsdk: no profile %q in config", name)
+	}
+	return c.resolve(p), nil
+}
+
+// resolve replaces an Environment defined in the Config with its APIRoot
+// and OAuthEndpoint, unless the Profile sets them itself. Registered
+// Environments are left for WithProfile to resolve.
+func (c Config) resolve(p Profile) Profile {
+	if env, ok := c.Environments[p.Environment]; ok && p.Environment != "" {
+		p.Environment = ""
+		p = Profile{APIRoot: env.APIRoot, OAuthEndpoint: env.OAuthEndpoint}.Override(p)
+	}
+	return p
+}
+
+// ProfileFromEnv builds a Profile from the ESP_* environment variables.
+// Unset variables leave the corresponding fields empty.
+func ProfileFromEnv() Profile {
+	return Profile{
+		Environment:   os.Getenv(EnvEnvironment),
+		APIRoot:       os.Getenv(EnvAPIRoot),
+		OAuthEndpoint: os.Getenv(EnvOAuthEndpoint),
+		APIKey:        os.Getenv(EnvAPIKey),
+		APISecret:     os.Getenv(EnvAPISecret),
+		Username:      os.Getenv(EnvUsername),
+		Password:      os.Getenv(EnvPassword),
+		TokenFile:     os.Getenv(EnvTokenFile),
+	}
+}
+
+// Override returns a copy of the Profile in which every field that is set
+// in other replaces its counterpart. An Environment in other also discards
+// the APIRoot and OAuthEndpoint of the Profile, which would otherwise take
+// precedence over it.
+func (p Profile) Override(other Profile) Profile {
+	set := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	if other.Environment != "" {
+		p.APIRoot, p.OAuthEndpoint = "", ""
+	}
+	set(&p.Environment, other.Environment)
+	set(&p.APIRoot, other.APIRoot)
+	set(&p.OAuthEndpoint, other.OAuthEndpoint)
+	set(&p.APIKey, other.APIKey)
+	set(&p.APISecret, other.APISecret)
+	set(&p.Username, other.Username)
+	set(&p.Password, other.Password)
+	set(&p.TokenFile, other.TokenFile)
+	return p
+}
+
+// WithProfile applies the Profile's credentials, Environment and token
+// file to the Client.
+func WithProfile(p Profile) ClientOption {
+	return func(c *Client) error {
+		if p.Environment != "" {
+			if err := WithEnvironment(p.Environment)(c); err != nil {
+				return err
+			}
+		}
+		if p.APIRoot != "" {
+			c.apiRoot = p.APIRoot
+		}
+		if p.OAuthEndpoint != "" {
+			c.OAuthEndpoint = p.OAuthEndpoint
+		}
+		if p.TokenFile != "" {
+			c.TokenStore = NewFileTokenStore(p.TokenFile)
+		}
+		return WithCredentials(p.APIKey, p.APISecret, p.Username, p.Password)(c)
+	}
+}
+
+// NewClientFromConfig builds a Client from the named profile of the config
+// file at path, with any ESP_* environment variables taking precedence
+// over the file. An empty path falls back to $ESP_CONFIG and, if that is
+// unset too, the Client is configured from the environment alone. An empty
+// profile falls back to $ESP_PROFILE and then the file's default profile.
+// Further options are applied last.
+func NewClientFromConfig(path, profile string, opts ...ClientOption) (Client, error) {
+	if path == "" {
+		path = os.Getenv(EnvConfig)
+	}
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+
+	var (
+		config Config
+		p      Profile
+	)
+	if path != "" {
+		var err error
+		if config, err = LoadConfig(path); err != nil {
+			return Client{}, err
+		}
+		if p, err = config.Profile(profile); err != nil {
+			return Client{}, err
+		}
+	}
+	p = config.resolve(p.Override(ProfileFromEnv()))
+	return NewClient(append([]ClientOption{WithProfile(p)}, opts...)...)
+}
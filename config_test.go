@@ -0,0 +1,135 @@
+package espsdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testTOMLConfig = `
+default_profile = "staging"
+
+[environments.qa]
+api_root = "https://esp-qa.example.com/esp"
+oauth_endpoint = "https://esp-qa.example.com/oauth2/token"
+
+[profiles.staging]
+environment = "qa"
+api_key = "qa_key"
+api_secret = "qa_secret"
+username = "qa_user"
+password = "qa_pass"
+
+[profiles.live]
+environment = "production"
+api_key = "live_key"
+api_secret = "live_secret"
+`
+
+const testJSONConfig = `{
+	"profiles": {
+		"default": {"environment": "sandbox", "api_key": "k", "api_secret": "s", "token_file": "/tmp/esp.json"}
+	}
+}`
+
+func writeConfig(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLookupEnvironment(t *testing.T) {
+	env, err := LookupEnvironment("Prod")
+	if err != nil || env.APIRoot != ProdAPI || env.OAuthEndpoint != OAuthEndpoint {
+		t.Errorf("got %+v, %v", env, err)
+	}
+	if _, err := LookupEnvironment("oregon"); err == nil {
+		t.Errorf("unknown environments should be rejected")
+	}
+	if err := RegisterEnvironment(Environment{Name: "oregon"}); err == nil {
+		t.Errorf("incomplete environments should be rejected")
+	}
+}
+
+func TestConfigProfilesFromTOML(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, "esp.toml", testTOMLConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	staging, err := config.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging.APIRoot != "https://esp-qa.example.com/esp" || staging.Username != "qa_user" {
+		t.Errorf("unexpected default profile: %+v", staging)
+	}
+
+	c, err := NewClient(WithProfile(staging))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.OAuthEndpoint != "https://esp-qa.example.com/oauth2/token" {
+		t.Errorf("got OAuth endpoint %q", c.OAuthEndpoint)
+	}
+
+	live, _ := config.Profile("live")
+	c, err = NewClient(WithProfile(live))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.apiRoot != ProdAPI {
+		t.Errorf("got API root %q", c.apiRoot)
+	}
+
+	if _, err := config.Profile("missing"); err == nil {
+		t.Errorf("a missing profile should be an error")
+	}
+}
+
+func TestNewClientFromConfigPrefersEnvironment(t *testing.T) {
+	path := writeConfig(t, "esp.json", testJSONConfig)
+	t.Setenv(EnvConfig, path)
+	t.Setenv(EnvAPISecret, "from_env")
+
+	c, err := NewClientFromConfig("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.apiRoot != SandboxAPI || c.OAuthEndpoint != OAuthEndpoint {
+		t.Errorf("got %q and %q", c.apiRoot, c.OAuthEndpoint)
+	}
+	if c.credentials.APIKey != "k" || c.credentials.APISecret != "from_env" {
+		t.Errorf("got credentials %+v", c.credentials)
+	}
+	if store, ok := c.TokenStore.(*FileTokenStore); !ok || store.Path != "/tmp/esp.json" {
+		t.Errorf("got token store %#v", c.TokenStore)
+	}
+}
+
+func TestEnvironmentVariableOverridesProfileEndpoints(t *testing.T) {
+	path := writeConfig(t, "esp.toml", testTOMLConfig)
+	t.Setenv(EnvEnvironment, Sandbox)
+
+	c, err := NewClientFromConfig(path, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.apiRoot != SandboxAPI || c.OAuthEndpoint != OAuthEndpoint {
+		t.Errorf("got %q and %q", c.apiRoot, c.OAuthEndpoint)
+	}
+	if c.credentials.APIKey != "qa_key" {
+		t.Errorf("got credentials %+v", c.credentials)
+	}
+
+	t.Setenv(EnvEnvironment, "qa")
+	t.Setenv(EnvOAuthEndpoint, "https://auth.example.com/token")
+	if c, err = NewClientFromConfig(path, "live"); err != nil {
+		t.Fatal(err)
+	}
+	if c.apiRoot != "https://esp-qa.example.com/esp" || c.OAuthEndpoint != "https://auth.example.com/token" {
+		t.Errorf("got %q and %q", c.apiRoot, c.OAuthEndpoint)
+	}
+}
@@ -1,6 +1,7 @@
 package espsdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,91 +13,94 @@ import (
 // A Contribution is the metadata that represents a media asset from
 // a contributor.
 type Contribution struct {
-	AdditionalFacialExpressions []map[string]interface{} `json:"additional_facial_expressions,omitempty"`
-	AlternateID                 string                   `json:"alternate_id,omitempty"`
-	CallForImage                bool                     `json:"call_for_image,omitempty"`
-	CameraShotDate              string                   `json:"camera_shot_date,omitempty"`
-	Caption                     string                   `json:"caption,omitempty"`
-	City                        string                   `json:"city,omitempty"`
-	CollectionCode              string                   `json:"collection_code,omitempty"`
-	ContentProviderName         string                   `json:"content_provider_name,omitempty"`
-	ContentProviderTitle        string                   `json:"content_provider_title,omitempty"`
-	ContentWarnings             string                   `json:"content_warnings,omitempty"`
-	Copyright                   string                   `json:"copyright,omitempty"`
-	CountryOfShoot              string                   `json:"country_of_shoot,omitempty"`
-	CreatedAt                   *time.Time               `json:"created_at,omitempty"`
-	CreatedDate                 *time.Time               `json:"created_date,omitempty"`
-	CreditLine                  string                   `json:"credit_line,omitempty"`
-	DSAAlternateIds             map[string]string        `json:"dsa_alternate_ids,omitempty"`
-	Errors                      interface{}              `json:"errors,omitempty"`
-	EventID                     string                   `json:"event_id,omitempty"`
-	ExclusionRoutes             string                   `json:"exclusion_routes,omitempty"`
-	ExclusiveCoverage           bool                     `json:"exclusive_coverage,omitempty"`
-	ExternalFileLocation        string                   `json:"external_file_location,omitempty"`
-	ExtractedMetadataPresent    bool                     `json:"extracted_metadata_present,omitempty"`
-	FacialExpressions           []TermItem               `json:"facial_expressions,omitempty"`
-	FileName                    string                   `json:"file_name,omitempty"`
-	FilePath                    string                   `json:"file_path,omitempty"`
-	FileUploaded                bool                     `json:"file_uploaded,omitempty"`
-	FinalBucket                 string                   `json:"final_bucket,omitempty"`
-	Headline                    string                   `json:"headline,omitempty"`
-	ID                          string                   `json:"id,omitempty"`
-	IPTCCaptionWriter           string                   `json:"iptc_caption_writer,omitempty"`
-	IPTCCategory                string                   `json:"iptc_category,omitempty"`
-	IPTCSubjects                []string                 `json:"iptc_subjects,omitempty"`
-	ImageHeight                 int                      `json:"image_height,omitempty"`
-	ImageWidth                  int                      `json:"image_width,omitempty"`
-	InactiveDate                *time.Time               `json:"inactive_date,omitempty"`
-	InclusionRoutes             interface{}              `json:"inclusion_routes,omitempty"`
-	Keywords                    []Keyword                `json:"keywords,omitempty"`
-	MasterID                    string                   `json:"master_id,omitempty"`
-	MediaType                   string                   `json:"media_type,omitempty"`
-	MetadataExtractionStartedAt *time.Time               `json:"metadata_extraction_started_at,omitempty"`
-	MetadataExtractionTimeout   bool                     `json:"metadata_extraction_timeout,omitempty"`
-	MimeType                    string                   `json:"mime_type,omitempty"`
+	AdditionalFacialExpressions FacialExpressions  `json:"additional_facial_expressions,omitempty"`
+	AlternateID                 string             `json:"alternate_id,omitempty"`
+	CallForImage                bool               `json:"call_for_image,omitempty"`
+	CameraShotDate              string             `json:"camera_shot_date,omitempty"`
+	Caption                     string             `json:"caption,omitempty"`
+	City                        string             `json:"city,omitempty"`
+	CollectionCode              string             `json:"collection_code,omitempty"`
+	ContentProviderName         string             `json:"content_provider_name,omitempty"`
+	ContentProviderTitle        string             `json:"content_provider_title,omitempty"`
+	ContentWarnings             string             `json:"content_warnings,omitempty"`
+	Copyright                   string             `json:"copyright,omitempty"`
+	CountryOfShoot              string             `json:"country_of_shoot,omitempty"`
+	CreatedAt                   *time.Time         `json:"created_at,omitempty"`
+	CreatedDate                 *time.Time         `json:"created_date,omitempty"`
+	CreditLine                  string             `json:"credit_line,omitempty"`
+	DSAAlternateIds             map[string]string  `json:"dsa_alternate_ids,omitempty"`
+	Errors                      ContributionErrors `json:"errors,omitempty"`
+	EventID                     string             `json:"event_id,omitempty"`
+	ExclusionRoutes             string             `json:"exclusion_routes,omitempty"`
+	ExclusiveCoverage           bool               `json:"exclusive_coverage,omitempty"`
+	ExternalFileLocation        string             `json:"external_file_location,omitempty"`
+	ExtractedMetadataPresent    bool               `json:"extracted_metadata_present,omitempty"`
+	FacialExpressions           []TermItem         `json:"facial_expressions,omitempty"`
+	FileName                    string             `json:"file_name,omitempty"`
+	FilePath                    string             `json:"file_path,omitempty"`
+	FileUploaded                bool               `json:"file_uploaded,omitempty"`
+	FinalBucket                 string             `json:"final_bucket,omitempty"`
+	Headline                    string             `json:"headline,omitempty"`
+	ID                          string             `json:"id,omitempty"`
+	IPTCCaptionWriter           string             `json:"iptc_caption_writer,omitempty"`
+	IPTCCategory                string             `json:"iptc_category,omitempty"`
+	IPTCSubjects                []string           `json:"iptc_subjects,omitempty"`
+	ImageHeight                 int                `json:"image_height,omitempty"`
+	ImageWidth                  int                `json:"image_width,omitempty"`
+	InactiveDate                *time.Time         `json:"inactive_date,omitempty"`
+	InclusionRoutes             Routes             `json:"inclusion_routes,omitempty"`
+	Keywords                    []Keyword          `json:"keywords,omitempty"`
+	MasterID                    string             `json:"master_id,omitempty"`
+	MediaType                   string             `json:"media_type,omitempty"`
+	MetadataExtractionStartedAt *time.Time         `json:"metadata_extraction_started_at,omitempty"`
+	MetadataExtractionTimeout   bool               `json:"metadata_extraction_timeout,omitempty"`
+	MimeType                    string             `json:"mime_type,omitempty"`
 	// NumberOfPeople              TermItemInt              `json:"number_of_people,omitempty"`
-	PaidAssignment            bool        `json:"paid_assignment,omitempty"`
-	PaidAssignmentID          string      `json:"paid_assignment_id,omitempty"`
-	ParentSource              string      `json:"parent_source,omitempty"`
-	PersonCompositions        []TermItem  `json:"person_compositions,omitempty"`
-	Personalities             []Keyword   `json:"personalities,omitempty"`
-	PicscoutSuggestions       interface{} `json:"picscout_suggestions,omitempty"`
-	ProvinceState             string      `json:"province_state,omitempty"`
-	PublicistApprovalRequired bool        `json:"publicist_approval_required,omitempty"`
-	PublishedAt               *time.Time  `json:"published_at,omitempty"`
-	PulledReason              string      `json:"pulled_reason,omitempty"`
-	Rank                      int         `json:"rank,omitempty"`
-	ReadyForSale              bool        `json:"ready_for_sale,omitempty"`
-	RecordedDate              string      `json:"recorded_date,omitempty"`
-	RiskCategory              string      `json:"risk_category,omitempty"`
-	ShotSpeed                 string      `json:"shot_speed,omitempty"`
-	SiteDestination           []string    `json:"site_destination,omitempty"`
-	Source                    string      `json:"source,omitempty"`
-	SpecialInstructions       string      `json:"special_instructions,omitempty"`
-	Status                    string      `json:"status,omitempty"`
-	StorageURL                string      `json:"storage_url,omitempty"`
-	SubmissionBatchID         string      `json:"submission_batch_id,omitempty"`
-	Submittable               bool        `json:"submittable,omitempty"`
-	SubmittedAt               *time.Time  `json:"submitted_at,omitempty"`
-	SubmittedToReviewAt       string      `json:"submitted_to_review_at,omitempty"`
-	ThumbnailURL              string      `json:"thumbnail_url,omitempty"`
-	UpdatedAt                 *time.Time  `json:"updated_at,omitempty"`
-	UploadBucket              string      `json:"upload_bucket,omitempty"`
-	UploadID                  string      `json:"upload_id,omitempty"`
-	UserMetadataValid         bool        `json:"user_metadata_valid,omitempty"`
-	VisualColor               string      `json:"visual_color,omitempty"`
+	PaidAssignment            bool                `json:"paid_assignment,omitempty"`
+	PaidAssignmentID          string              `json:"paid_assignment_id,omitempty"`
+	ParentSource              string              `json:"parent_source,omitempty"`
+	PersonCompositions        []TermItem          `json:"person_compositions,omitempty"`
+	Personalities             []Keyword           `json:"personalities,omitempty"`
+	PicscoutSuggestions       PicscoutSuggestions `json:"picscout_suggestions,omitempty"`
+	ProvinceState             string              `json:"province_state,omitempty"`
+	PublicistApprovalRequired bool                `json:"publicist_approval_required,omitempty"`
+	PublishedAt               *time.Time          `json:"published_at,omitempty"`
+	PulledReason              string              `json:"pulled_reason,omitempty"`
+	Rank                      int                 `json:"rank,omitempty"`
+	ReadyForSale              bool                `json:"ready_for_sale,omitempty"`
+	RecordedDate              string              `json:"recorded_date,omitempty"`
+	RiskCategory              string              `json:"risk_category,omitempty"`
+	ShotSpeed                 string              `json:"shot_speed,omitempty"`
+	SiteDestination           []string            `json:"site_destination,omitempty"`
+	Source                    string              `json:"source,omitempty"`
+	SpecialInstructions       string              `json:"special_instructions,omitempty"`
+	Status                    string              `json:"status,omitempty"`
+	StorageURL                string              `json:"storage_url,omitempty"`
+	SubmissionBatchID         string              `json:"submission_batch_id,omitempty"`
+	Submittable               bool                `json:"submittable,omitempty"`
+	SubmittedAt               *time.Time          `json:"submitted_at,omitempty"`
+	SubmittedToReviewAt       string              `json:"submitted_to_review_at,omitempty"`
+	ThumbnailURL              string              `json:"thumbnail_url,omitempty"`
+	UpdatedAt                 *time.Time          `json:"updated_at,omitempty"`
+	UploadBucket              string              `json:"upload_bucket,omitempty"`
+	UploadID                  string              `json:"upload_id,omitempty"`
+	UserMetadataValid         bool                `json:"user_metadata_valid,omitempty"`
+	VisualColor               string              `json:"visual_color,omitempty"`
 }
 
 // Submit requests that the contribution be submitted for review and
 // publication.
 func (c Contribution) Submit(client sleepwalker.RESTClient) (sleepwalker.Result, error) {
+	result, err := c.SubmitContext(context.Background(), contextClient(client))
+	return result.Result, err
+}
+
+// SubmitContext is like Submit but sends the request with the provided
+// context.
+func (c Contribution) SubmitContext(ctx context.Context, client ContextClient) (Result, error) {
 	desc := "Contribution.Submit"
-	result, err := client.Put(c, c.Path()+"/submit")
-	if err != nil {
-		result.Log().Error(desc)
-		return result, err
-	}
-	if result.StatusCode == 404 {
+	result, err := client.PutContext(ctx, c, c.Path()+"/submit")
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
 		return result, err
 	}
@@ -106,18 +110,28 @@ func (c Contribution) Submit(client sleepwalker.RESTClient) (sleepwalker.Result,
 
 // CreateAndSubmit creates a Contribution and submits it for review/publication.
 func (c Contribution) CreateAndSubmit(client sleepwalker.RESTClient) (sleepwalker.Result, error) {
+	result, err := c.CreateAndSubmitContext(context.Background(), contextClient(client))
+	return result.Result, err
+}
+
+// CreateAndSubmitContext is like CreateAndSubmit but sends its requests with
+// the provided context.
+func (c Contribution) CreateAndSubmitContext(ctx context.Context, client ContextClient) (Result, error) {
 	desc := "Contribution.CreateAndSubmit"
-	result, err := client.Create(c)
-	if err != nil {
+	result, err := client.CreateContext(ctx, c)
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
 		return result, err
 	}
 	result.Log().Debug(desc)
 
 	var savedContribution Contribution
-	json.Unmarshal(result.Payload, &savedContribution)
-	result, err = client.Put(savedContribution, savedContribution.Path()+"/submit")
-	if err != nil {
+	if err = json.Unmarshal(result.Payload, &savedContribution); err != nil {
+		result.Log().WithField("error", err).Error(desc)
+		return result, err
+	}
+	result, err = client.PutContext(ctx, savedContribution, savedContribution.Path()+"/submit")
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
 		return result, err
 	}
@@ -125,23 +139,89 @@ func (c Contribution) CreateAndSubmit(client sleepwalker.RESTClient) (sleepwalke
 	return result, nil
 }
 
+// Create asks ESP to add the Contribution to the Batch named by its
+// SubmissionBatchID, returning the Contribution as saved.
+func (c Contribution) Create(ctx context.Context, client ContextClient) (*Contribution, error) {
+	desc := "Contribution.Create"
+	if c.SubmissionBatchID == "" {
+		return nil, ErrMissingID
+	}
+	c.ID = ""
+	result, err := client.CreateContext(ctx, c)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return nil, err
+	}
+	result.Log().Info(desc)
+	return Contribution{}.Unmarshal(result.Payload)
+}
+
+// Get requests the Contribution with the receiver's SubmissionBatchID and
+// ID.
+func (c Contribution) Get(ctx context.Context, client ContextClient) (*Contribution, error) {
+	desc := "Contribution.Get"
+	if c.SubmissionBatchID == "" || c.ID == "" {
+		return nil, ErrMissingID
+	}
+	result, err := client.GetContext(ctx, c)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return nil, err
+	}
+	result.Log().Info(desc)
+	return Contribution{}.Unmarshal(result.Payload)
+}
+
+// Update replaces the metadata of the saved Contribution with the same ID,
+// returning the Contribution as saved.
+func (c Contribution) Update(ctx context.Context, client ContextClient) (*Contribution, error) {
+	desc := "Contribution.Update"
+	if c.SubmissionBatchID == "" || c.ID == "" {
+		return nil, ErrMissingID
+	}
+	result, err := client.PutContext(ctx, ContributionUpdate{c}, c.Path())
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return nil, err
+	}
+	result.Log().Info(desc)
+	return Contribution{}.Unmarshal(result.Payload)
+}
+
+// Delete deletes the Contribution with the receiver's SubmissionBatchID and
+// ID.
+func (c Contribution) Delete(ctx context.Context, client ContextClient) error {
+	desc := "Contribution.Delete"
+	if c.SubmissionBatchID == "" || c.ID == "" {
+		return ErrMissingID
+	}
+	result, err := client.DeleteContext(ctx, c)
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		return err
+	}
+	result.Log().Info(desc)
+	return nil
+}
+
 // Index requests a list of all Contributions associated with the specified
 // Submission Batch.
-func (c Contribution) Index(client sleepwalker.RESTClient, batchID string) ContributionList {
+func (c Contribution) Index(client sleepwalker.RESTClient, batchID string) (ContributionList, error) {
+	return c.IndexContext(context.Background(), contextClient(client), batchID)
+}
+
+// IndexContext is like Index but sends the request with the provided
+// context.
+func (c Contribution) IndexContext(ctx context.Context, client ContextClient, batchID string) (ContributionList, error) {
 	desc := "Contribution.Index"
 	c.SubmissionBatchID = batchID
-	result, err := client.Get(c)
-	if err != nil {
+	result, err := client.GetContext(ctx, c)
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
-		return ContributionList{}
-	}
-	if result.StatusCode == 404 {
-		result.Log().Error(desc)
-		return ContributionList{}
+		return ContributionList{}, err
 	}
 	result.Log().Info(desc)
-	contributionList, _ := ContributionList{}.Unmarshal(result.Payload)
-	return contributionList
+	return ContributionList{}.Unmarshal(result.Payload)
 }
 
 // Path returns the path for the contribution.
@@ -0,0 +1,281 @@
+package espsdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ContributionErrors are the problems ESP found with a Contribution, by
+// field. ESP sends them as a map of field names to messages or as a list of
+// messages; both are accepted, and any other shape is decoded as no errors.
+// Messages not about a particular field are under "base".
+type ContributionErrors []FieldError
+
+// UnmarshalJSON accepts each of the shapes ESP uses.
+func (ce *ContributionErrors) UnmarshalJSON(data []byte) error {
+	*ce = nil
+	var raw interface{}
+	if isEmptyJSON(data) || json.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		*ce = parseFieldErrors(data)
+	case []interface{}:
+		var messages []string
+		for _, item := range v {
+			if message, ok := item.(string); ok {
+				messages = append(messages, message)
+			}
+		}
+		if len(messages) > 0 {
+			*ce = ContributionErrors{{Field: "base", Messages: messages}}
+		}
+	case string:
+		*ce = ContributionErrors{{Field: "base", Messages: []string{v}}}
+	}
+	return nil
+}
+
+// MarshalJSON writes the errors as a map of field names to messages.
+func (ce ContributionErrors) MarshalJSON() ([]byte, error) {
+	byField := make(map[string][]string, len(ce))
+	for _, fe := range ce {
+		byField[fe.Field] = append(byField[fe.Field], fe.Messages...)
+	}
+	return json.Marshal(byField)
+}
+
+// Messages returns the messages about the field.
+func (ce ContributionErrors) Messages(field string) []string {
+	for _, fe := range ce {
+		if fe.Field == field {
+			return fe.Messages
+		}
+	}
+	return nil
+}
+
+// Routes are the distribution routes of a Contribution, such as its
+// InclusionRoutes. ESP sends them as a list of names, a comma-separated
+// string, or a list of objects with a name; all are accepted, and any other
+// shape is decoded as no routes.
+type Routes []string
+
+// UnmarshalJSON accepts each of the shapes ESP uses.
+func (r *Routes) UnmarshalJSON(data []byte) error {
+	*r = nil
+	var raw interface{}
+	if isEmptyJSON(data) || json.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+	var routes Routes
+	switch v := raw.(type) {
+	case string:
+		for _, route := range strings.Split(v, ",") {
+			if route = strings.TrimSpace(route); route != "" {
+				routes = append(routes, route)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				routes = append(routes, item)
+			case map[string]interface{}:
+				for _, key := range []string{"name", "value"} {
+					if name, ok := item[key].(string); ok {
+						routes = append(routes, name)
+						break
+					}
+				}
+			}
+		}
+	}
+	*r = routes
+	return nil
+}
+
+// A PicscoutSuggestion is an existing image that PicScout found to resemble
+// a Contribution. Fields ESP sends that are not modeled are kept in Extra.
+type PicscoutSuggestion struct {
+	ID           string                 `json:"id,omitempty"`
+	Title        string                 `json:"title,omitempty"`
+	URL          string                 `json:"url,omitempty"`
+	ThumbnailURL string                 `json:"thumbnail_url,omitempty"`
+	Score        float64                `json:"score,omitempty"`
+	Extra        map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON accepts IDs and scores as strings or numbers, and
+// image_url for url.
+func (ps *PicscoutSuggestion) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	take := func(keys ...string) interface{} {
+		for _, key := range keys {
+			if v, ok := raw[key]; ok {
+				delete(raw, key)
+				return v
+			}
+		}
+		return nil
+	}
+	*ps = PicscoutSuggestion{
+		ID:           looseString(take("id")),
+		Title:        looseString(take("title")),
+		URL:          looseString(take("url", "image_url")),
+		ThumbnailURL: looseString(take("thumbnail_url")),
+		Score:        looseFloat(take("score")),
+	}
+	if len(raw) > 0 {
+		ps.Extra = raw
+	}
+	return nil
+}
+
+// MarshalJSON writes the modeled fields together with Extra, so that a
+// suggestion survives a round trip.
+func (ps PicscoutSuggestion) MarshalJSON() ([]byte, error) {
+	out := make(map[string]interface{}, len(ps.Extra)+5)
+	for k, v := range ps.Extra {
+		out[k] = v
+	}
+	type modeled PicscoutSuggestion
+	known, err := json.Marshal(modeled(ps))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(known, &out); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// PicscoutSuggestions are the PicscoutSuggestions for a Contribution. ESP
+// sends them as a list, or as an object holding the list under
+// "suggestions" or "items"; both are accepted. Items that are not objects,
+// and any other shape, are ignored.
+type PicscoutSuggestions []PicscoutSuggestion
+
+// UnmarshalJSON accepts each of the shapes ESP uses.
+func (ps *PicscoutSuggestions) UnmarshalJSON(data []byte) error {
+	*ps = nil
+	var wrapped map[string]json.RawMessage
+	if json.Unmarshal(data, &wrapped) == nil {
+		for _, key := range []string{"suggestions", "items"} {
+			if inner, ok := wrapped[key]; ok {
+				return ps.UnmarshalJSON(inner)
+			}
+		}
+		return nil
+	}
+	var items []json.RawMessage
+	if json.Unmarshal(data, &items) != nil {
+		return nil
+	}
+	var list PicscoutSuggestions
+	for _, item := range items {
+		var suggestion PicscoutSuggestion
+		if isObject(item) && json.Unmarshal(item, &suggestion) == nil {
+			list = append(list, suggestion)
+		}
+	}
+	*ps = list
+	return nil
+}
+
+// A FacialExpression is a TermItem describing the expression of a person
+// in a Contribution.
+type FacialExpression TermItem
+
+// UnmarshalJSON accepts a TermItem object, whose term_id may be a string or
+// a number, or a bare term ID.
+func (fe *FacialExpression) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		*fe = FacialExpression{
+			Term:     looseString(v["term"]),
+			TermID:   looseString(v["term_id"]),
+			HelpText: looseString(v["help_text"]),
+			ImageURI: looseString(v["image_uri"]),
+		}
+	case string, float64:
+		*fe = FacialExpression{TermID: looseString(v)}
+	default:
+		return fmt.Errorf("espsdk: unrecognized facial expression %s", data)
+	}
+	return nil
+}
+
+// FacialExpressions are the FacialExpressions of a Contribution. Items that
+// are neither a TermItem nor a term ID, and any shape other than a list,
+// are ignored.
+type FacialExpressions []FacialExpression
+
+// UnmarshalJSON keeps the items it recognizes.
+func (fes *FacialExpressions) UnmarshalJSON(data []byte) error {
+	*fes = nil
+	var items []json.RawMessage
+	if json.Unmarshal(data, &items) != nil {
+		return nil
+	}
+	var list FacialExpressions
+	for _, item := range items {
+		var fe FacialExpression
+		if json.Unmarshal(item, &fe) == nil {
+			list = append(list, fe)
+		}
+	}
+	*fes = list
+	return nil
+}
+
+// isEmptyJSON reports whether data is a JSON null, empty string, empty
+// object or empty list, which ESP sends interchangeably for "none".
+func isEmptyJSON(data []byte) bool {
+	switch string(bytes.TrimSpace(data)) {
+	case "", "null", `""`, "{}", "[]":
+		return true
+	}
+	return false
+}
+
+// isObject reports whether data is a JSON object.
+func isObject(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// looseString converts a decoded JSON string or number to a string.
+func looseString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return ""
+}
+
+// looseFloat converts a decoded JSON number or numeric string to a float64.
+func looseFloat(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package espsdk_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+)
+
+func TestContributionErrorsShapes(t *testing.T) {
+	cases := map[string]espsdk.ContributionErrors{
+		`{"headline": ["can't be blank"], "caption": "is too long"}`: {
+			{Field: "caption", Messages: []string{"is too long"}},
+			{Field: "headline", Messages: []string{"can't be blank"}},
+		},
+		`["file is corrupt"]`: {{Field: "base", Messages: []string{"file is corrupt"}}},
+		`"processing failed"`: {{Field: "base", Messages: []string{"processing failed"}}},
+		`null`:                nil,
+		`{}`:                  nil,
+	}
+	for payload, want := range cases {
+		var got espsdk.ContributionErrors
+		if err := json.Unmarshal([]byte(payload), &got); err != nil {
+			t.Errorf("%s: %v", payload, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", payload, got, want)
+		}
+	}
+}
+
+func TestRoutesShapes(t *testing.T) {
+	for _, payload := range []string{
+		`["Getty", "iStock"]`,
+		`"Getty, iStock"`,
+		`[{"name": "Getty"}, {"value": "iStock"}]`,
+	} {
+		var got espsdk.Routes
+		if err := json.Unmarshal([]byte(payload), &got); err != nil {
+			t.Errorf("%s: %v", payload, err)
+		} else if !reflect.DeepEqual(got, espsdk.Routes{"Getty", "iStock"}) {
+			t.Errorf("%s: got %q", payload, got)
+		}
+	}
+}
+
+func TestContributionDecodesTypedFields(t *testing.T) {
+	payload := `{
+		"id": "1",
+		"errors": {"headline": ["can't be blank"]},
+		"inclusion_routes": "Getty",
+		"picscout_suggestions": {"suggestions": [
+			{"id": 123456789, "image_url": "https://example.com/1", "score": "0.93", "owner": "AFP"}
+		]},
+		"additional_facial_expressions": [{"term": "Smiling", "term_id": 76}, "77"]
+	}`
+	var c espsdk.Contribution
+	if err := json.Unmarshal([]byte(payload), &c); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Errors.Messages("headline"); !reflect.DeepEqual(got, []string{"can't be blank"}) {
+		t.Errorf("got errors %+v", c.Errors)
+	}
+	if !reflect.DeepEqual(c.InclusionRoutes, espsdk.Routes{"Getty"}) {
+		t.Errorf("got routes %q", c.InclusionRoutes)
+	}
+	want := espsdk.PicscoutSuggestion{ID: "123456789", URL: "https://example.com/1", Score: 0.93,
+		Extra: map[string]interface{}{"owner": "AFP"}}
+	if len(c.PicscoutSuggestions) != 1 || !reflect.DeepEqual(c.PicscoutSuggestions[0], want) {
+		t.Errorf("got suggestions %+v", c.PicscoutSuggestions)
+	}
+	if fe := c.AdditionalFacialExpressions; len(fe) != 2 || fe[0].TermID != "76" || fe[0].Term != "Smiling" || fe[1].TermID != "77" {
+		t.Errorf("got facial expressions %+v", fe)
+	}
+
+	// The typed fields survive a round trip.
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded espsdk.Contribution
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, c) {
+		t.Errorf("got %+v\nwant %+v", decoded, c)
+	}
+}
+
+func TestContributionIgnoresUnknownShapes(t *testing.T) {
+	for _, payload := range []string{
+		`{"errors": false}`,
+		`{"errors": 42}`,
+		`{"errors": [{"field": "headline", "message": "can't be blank"}]}`,
+		`{"inclusion_routes": 123}`,
+		`{"inclusion_routes": {"Getty": true}}`,
+		`{"picscout_suggestions": ["abc"]}`,
+		`{"picscout_suggestions": {"count": 0}}`,
+		`{"additional_facial_expressions": [null]}`,
+		`{"additional_facial_expressions": 5}`,
+	} {
+		var c espsdk.Contribution
+		if err := json.Unmarshal([]byte(payload), &c); err != nil {
+			t.Errorf("%s: %v", payload, err)
+			continue
+		}
+		if c.Errors != nil || c.InclusionRoutes != nil || c.PicscoutSuggestions != nil || c.AdditionalFacialExpressions != nil {
+			t.Errorf("%s: got %+v", payload, c)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package espsdk_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestContributionCRUD(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still"})
+
+	created, err := espsdk.Contribution{SubmissionBatchID: b.ID, Headline: "Dawn"}.Create(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Headline = "Dusk"
+	created.FileName = "dusk.jpg"
+	updated, err := created.Update(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Headline != "Dusk" || !updated.Submittable {
+		t.Errorf("got %+v", updated)
+	}
+
+	fetched, err := espsdk.Contribution{SubmissionBatchID: b.ID, ID: created.ID}.Get(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Headline != "Dusk" {
+		t.Errorf("got %+v", fetched)
+	}
+
+	if err := fetched.Delete(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetched.Get(ctx, client); !espsdk.IsNotFound(err) {
+		t.Errorf("got %v, want not found", err)
+	}
+	if _, err := (espsdk.Contribution{ID: created.ID}).Get(ctx, client); err != espsdk.ErrMissingID {
+		t.Errorf("got %v, want %v", err, espsdk.ErrMissingID)
+	}
+}
+
+func TestCreateAndSubmitReportsUnreadableResponses(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still"})
+	var submitted bool
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`<html>Created</html>`))
+			return true
+		case "PUT":
+			submitted = true
+		}
+		return false
+	}
+
+	_, err := espsdk.Contribution{SubmissionBatchID: b.ID}.CreateAndSubmitContext(context.Background(), srv.Client())
+	if err == nil {
+		t.Error("an unreadable response should be an error")
+	}
+	if submitted {
+		t.Error("nothing should be submitted without the created Contribution's ID")
+	}
+}
+
+func TestSubmitLastPhotoWithoutBatches(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	var paths []string
+	srv.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		paths = append(paths, r.URL.Path)
+		return false
+	}
+
+	if _, err := espsdk.SubmitLastPhotoContext(context.Background(), srv.Client()); err == nil {
+		t.Error("there is no photo to submit")
+	}
+	if len(paths) != 1 || paths[0] != espsdk.Endpoints.Batches {
+		t.Errorf("only the Batches should be requested: %v", paths)
+	}
+}
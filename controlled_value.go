@@ -1,10 +1,6 @@
 package espsdk
 
-import (
-	"encoding/json"
-
-	"github.com/dysolution/sleepwalker"
-)
+import "encoding/json"
 
 type cv struct {
 	// "friendly" description, suitable for an HTML form label
@@ -63,12 +59,12 @@ type ControlledValues struct {
 	ControlledFields map[string]map[string][]cv `json:"controlled_fields,omitempty"`
 }
 
-func parseCV(result sleepwalker.Result) ControlledValues {
+func parseCV(rawPayload []byte) ControlledValues {
 	var allCV ControlledValues
 	allCV.ControlledFields = make(map[string]map[string][]cv)
 
 	var payload map[string]interface{}
-	json.Unmarshal(result.Payload, &payload)
+	json.Unmarshal(rawPayload, &payload)
 
 	var batchTypes []string
 
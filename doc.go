@@ -14,13 +14,41 @@ First, configure the SDK's client with your credentials:
 		"esp_api_secret",
 		"esp_username",
 		"esp_password",
-		"oregon",
+		espsdk.SandboxAPI,
+		espsdk.Log,
 		)
 
-The client creates and sends a token along with each request. If you'd like
-to save and cache it, you can call GetToken directly:
+NewClient accepts the same settings as functional options, along with
+others such as timeouts, a proxy, a custom http.Client or TLS settings:
 
-	token := client.GetToken()
+	client, err := espsdk.NewClient(
+		espsdk.WithCredentials("esp_api_key", "esp_api_secret", "esp_username", "esp_password"),
+		espsdk.WithAPIRoot(espsdk.SandboxAPI),
+		espsdk.WithTimeout(30*time.Second),
+		espsdk.WithTokenStore(espsdk.NewFileTokenStore("/tmp/esp-token.json")),
+	)
+
+Environments can be selected by name ("production" or "sandbox", or any
+registered with RegisterEnvironment), and account profiles can be kept in a
+JSON or TOML config file, with ESP_* environment variables taking
+precedence:
+
+	client, err := espsdk.NewClientFromConfig("esp.toml", "sandbox")
+
+The client requests a token when it first needs one and reuses it until it
+expires. To share tokens between Clients, or between runs of a program, give
+them a TokenStore. A FileTokenStore keeps them in a file readable only by
+its owner:
+
+	store := espsdk.NewFileTokenStore("/tmp/esp-token.json")
+	client, err := espsdk.NewClient(
+		espsdk.WithCredentials("esp_api_key", "esp_api_secret", "esp_username", "esp_password"),
+		espsdk.WithTokenStore(store),
+	)
+
+A Client from GetClient can use one by setting its TokenStore field:
+
+	client.TokenStore = store
 
 Media assets, such as photos and videos (represented as Contributions) must be
 uploaded into Submission Batches. You can use the client to create a Submission Batch:
@@ -35,6 +63,18 @@ The list (index) of your Batches now includes the Batch you just created:
 
 	batches := client.Index(espsdk.Batches)
 
+Index returns only the first page of results. To visit every Batch for the
+account, use a BatchIterator, which requests further pages as needed
+(ContributionIterator and ReleaseIterator do the same within a Batch):
+
+    it := espsdk.NewBatchIterator(client)
+    for it.Next(ctx) {
+        fmt.Println(it.Batch().SubmissionName)
+    }
+    if err := it.Err(); err != nil {
+        // handle the failed request
+    }
+
 You can proceed from there to add contributions:
     batchID := 81421  // iterate "batches" above to get these
     data := espsdk.Contribution{
@@ -47,7 +87,7 @@ The list (index) of Contributions for the Batch now includes the one you
 just created:
 
     batchID := 81421
-    contributions := espsdk.Contribution{}.Index(&client, batchID)
+    contributions, err := espsdk.Contribution{}.Index(&client, batchID)
 
 You can also add Releases to a batch:
     batchID := 81421
@@ -62,12 +102,30 @@ The list (index) of Releases for the Batch now includes the one you
 just created:
 
     batchID := 81421
-    releases := espsdk.Release{}.Index(&client, batchID)
+    releases, err := espsdk.Release{}.Index(&client, batchID)
 
 Contributions, Releases, and Batches can be deleted as well:
     release := espsdk.Release{ID: 172421, SubmissionBatchID: 81421}
     client.Delete(release.Path())
 
+Requests that fail return an *APIError describing the ESP error payload,
+which can be inspected with IsNotFound, IsUnauthorized and IsValidation:
+
+    batches, err := espsdk.Batch{}.Index(&client)
+    if espsdk.IsUnauthorized(err) {
+        // check your credentials
+    }
+
+Every call also has a variant that accepts a context.Context, so that
+deadlines and cancellation propagate into the underlying HTTP requests:
+
+    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+    batches, err := espsdk.Batch{}.IndexContext(ctx, client)
+
+The esptest package provides an in-process fake of the ESP API for testing
+code built on the client without access to the sandbox.
+
 Each of the three main types has a consistent CRUD interface. Other API
 endpoints are expressed either as simple GETs or endpoints that perform
 auto-suggest against provided terms in order to match them to Getty's
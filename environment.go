@@ -0,0 +1,92 @@
+package espsdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// An Environment is a deployment of the ESP API together with the OAuth
+// endpoint that issues tokens for it.
+type Environment struct {
+	Name          string `json:"name" toml:"name"`
+	APIRoot       string `json:"api_root" toml:"api_root"`
+	OAuthEndpoint string `json:"oauth_endpoint" toml:"oauth_endpoint"`
+}
+
+// The names of the built-in Environments.
+const (
+	Production = "production"
+	Sandbox    = "sandbox"
+)
+
+var environments = struct {
+	sync.RWMutex
+	byName map[string]Environment
+}{
+	byName: map[string]Environment{
+		Production: {Production, ProdAPI, OAuthEndpoint},
+		Sandbox:    {Sandbox, SandboxAPI, OAuthEndpoint},
+	},
+}
+
+// environmentAliases are alternative names accepted by LookupEnvironment.
+var environmentAliases = map[string]string{
+	"prod": Production,
+}
+
+// RegisterEnvironment adds a custom Environment, or replaces one of the
+// same name, so that it can be selected by name with WithEnvironment or in
+// a config file.
+func RegisterEnvironment(env Environment) error {
+	if env.Name == "" || env.APIRoot == "" || env.OAuthEndpoint == "" {
+		return fmt.Errorf("espsdk: environment %q needs a name, API root and OAuth endpoint", env.Name)
+	}
+	environments.Lock()
+	defer environments.Unlock()
+	environments.byName[strings.ToLower(env.Name)] = env
+	return nil
+}
+
+// LookupEnvironment returns the registered Environment with the given name,
+// ignoring case.
+func LookupEnvironment(name string) (Environment, error) {
+	name = strings.ToLower(name)
+	if alias, ok := environmentAliases[name]; ok {
+		name = alias
+	}
+	environments.RLock()
+	defer environments.RUnlock()
+	env, ok := environments.byName[name]
+	if !ok {
+		return Environment{}, fmt.Errorf("espsdk: unknown environment %q (known: %s)",
+			name, strings.Join(environmentNames(), ", "))
+	}
+	return env, nil
+}
+
+// environmentNames lists the registered Environments. The caller must hold
+// the lock.
+func environmentNames() []string {
+	var names []string
+	for name := range environments.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithEnvironment points the Client at the named Environment's API root
+// and OAuth endpoint.
+func WithEnvironment(name string) ClientOption {
+	return func(c *Client) error {
+		env, err := LookupEnvironment(name)
+		if err != nil {
+			return err
+		}
+		c.apiRoot = env.APIRoot
+		c.OAuthEndpoint = env.OAuthEndpoint
+		return nil
+	}
+}
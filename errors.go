@@ -0,0 +1,160 @@
+package espsdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// An APIError is returned when the ESP API responds to a request with a
+// status code that indicates failure. It carries as much of the ESP error
+// payload as could be parsed.
+type APIError struct {
+	StatusCode  int
+	Message     string
+	FieldErrors []FieldError
+	RequestID   string
+	Payload     []byte
+}
+
+// A FieldError describes why the ESP API rejected the value of a field.
+type FieldError struct {
+	Field    string
+	Messages []string
+}
+
+// Error satisfies the error interface.
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	out := fmt.Sprintf("esp: %d %s", e.StatusCode, msg)
+	for _, fe := range e.FieldErrors {
+		out += fmt.Sprintf("; %s: %s", fe.Field, strings.Join(fe.Messages, ", "))
+	}
+	if e.RequestID != "" {
+		out += fmt.Sprintf(" (request %s)", e.RequestID)
+	}
+	return out
+}
+
+// newAPIError builds an APIError from the status code and body of a
+// response. ESP is not consistent about the shape of its error payloads, so
+// the known variants are tried in turn and anything unrecognized is kept in
+// Payload.
+func newAPIError(statusCode int, payload []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Payload: payload}
+
+	var body struct {
+		Message          string          `json:"message"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+		Errors           json.RawMessage `json:"errors"`
+		RequestID        string          `json:"request_id"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		apiErr.Message = strings.TrimSpace(string(payload))
+		return apiErr
+	}
+
+	switch {
+	case body.Message != "":
+		apiErr.Message = body.Message
+	case body.ErrorDescription != "":
+		apiErr.Message = body.ErrorDescription
+	default:
+		apiErr.Message = body.Error
+	}
+	apiErr.RequestID = body.RequestID
+	apiErr.FieldErrors = parseFieldErrors(body.Errors)
+	return apiErr
+}
+
+// parseFieldErrors accepts the "errors" member of an ESP error payload in
+// any of the forms the API returns: a map of field names to a message or a
+// list of messages, or a bare list of messages.
+func parseFieldErrors(raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var byField map[string]interface{}
+	if err := json.Unmarshal(raw, &byField); err == nil {
+		var fieldErrors []FieldError
+		for field, v := range byField {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:    field,
+				Messages: messageList(v),
+			})
+		}
+		sort.Slice(fieldErrors, func(i, j int) bool {
+			return fieldErrors[i].Field < fieldErrors[j].Field
+		})
+		return fieldErrors
+	}
+
+	var messages []interface{}
+	if err := json.Unmarshal(raw, &messages); err == nil && len(messages) > 0 {
+		return []FieldError{{Field: "base", Messages: messageList(messages)}}
+	}
+	return nil
+}
+
+func messageList(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		var out []string
+		for _, item := range val {
+			out = append(out, messageList(item)...)
+		}
+		return out
+	case nil:
+		return nil
+	default:
+		return []string{fmt.Sprintf("%v", val)}
+	}
+}
+
+// checkStatus returns an *APIError for any non-2xx status code.
+func checkStatus(statusCode int, payload []byte) error {
+	if statusCode < 200 || statusCode > 299 {
+		return newAPIError(statusCode, payload)
+	}
+	return nil
+}
+
+// IsNotFound reports whether err is an APIError for a missing resource.
+func IsNotFound(err error) bool { return hasStatus(err, http.StatusNotFound) }
+
+// IsUnauthorized reports whether err is an APIError caused by missing,
+// expired or rejected credentials.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized) || hasStatus(err, http.StatusForbidden)
+}
+
+// IsValidation reports whether err is an APIError caused by the ESP API
+// rejecting the values in a request.
+func IsValidation(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnprocessableEntity:
+		return true
+	case http.StatusBadRequest:
+		return len(apiErr.FieldErrors) > 0
+	}
+	return false
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
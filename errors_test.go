@@ -0,0 +1,68 @@
+package espsdk
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewAPIErrorParsesFieldErrors(t *testing.T) {
+	payload := []byte(`{
+		"message": "Validation failed",
+		"errors": {"submission_name": ["can't be blank"], "submission_type": "is invalid"},
+		"request_id": "abc-123"
+	}`)
+	e := newAPIError(422, payload)
+	if e.Message != "Validation failed" {
+		t.Errorf("got message %q", e.Message)
+	}
+	if e.RequestID != "abc-123" {
+		t.Errorf("got request id %q", e.RequestID)
+	}
+	if len(e.FieldErrors) != 2 {
+		t.Fatalf("got %d field errors, want 2", len(e.FieldErrors))
+	}
+	if e.FieldErrors[0].Field != "submission_name" || e.FieldErrors[0].Messages[0] != "can't be blank" {
+		t.Errorf("unexpected field error: %v", e.FieldErrors[0])
+	}
+	if e.FieldErrors[1].Messages[0] != "is invalid" {
+		t.Errorf("unexpected field error: %v", e.FieldErrors[1])
+	}
+}
+
+func TestNewAPIErrorToleratesUnstructuredPayloads(t *testing.T) {
+	e := newAPIError(502, []byte("Bad Gateway"))
+	if e.Message != "Bad Gateway" {
+		t.Errorf("got message %q", e.Message)
+	}
+
+	e = newAPIError(400, []byte(`{"errors": ["batch is closed"]}`))
+	if len(e.FieldErrors) != 1 || e.FieldErrors[0].Messages[0] != "batch is closed" {
+		t.Errorf("unexpected field errors: %v", e.FieldErrors)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	if err := checkStatus(200, nil); err != nil {
+		t.Errorf("a 200 should not be an error, got %v", err)
+	}
+	err := checkStatus(404, []byte(`{"message": "Not Found"}`))
+	if !IsNotFound(err) {
+		t.Errorf("a 404 should be reported by IsNotFound, got %v", err)
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	wrapped := fmt.Errorf("Batch.Index: %w", &APIError{StatusCode: 401})
+	if !IsUnauthorized(wrapped) {
+		t.Errorf("IsUnauthorized should see through wrapping")
+	}
+	if IsNotFound(wrapped) || IsValidation(wrapped) {
+		t.Errorf("a 401 is neither not-found nor a validation error")
+	}
+	if IsValidation(&APIError{StatusCode: 400}) {
+		t.Errorf("a 400 without field errors is not a validation error")
+	}
+	if !IsValidation(&APIError{StatusCode: 422}) {
+		t.Errorf("a 422 is a validation error")
+	}
+}
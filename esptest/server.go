@@ -0,0 +1,821 @@
+/*
+Package esptest provides an in-process fake of the ESP API for use in tests.
+
+A Server keeps Batches, Contributions and Releases in memory and answers the
+same paths as the ESP API, including OAuth token issue, so that code built
+on espsdk.GetClient can be exercised end to end without the sandbox:
+
+	srv := esptest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	batches, err := espsdk.Batch{}.IndexContext(ctx, client)
+
+The methods that take a sleepwalker.RESTClient, such as Batch.Index, work
+the same way when given the Client.
+*/
+package esptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dysolution/espsdk"
+)
+
+// The credentials a Server accepts unless they are changed before the first
+// request.
+const (
+	APIKey    = "esptest_key"
+	APISecret = "esptest_secret"
+	Username  = "esptest_user"
+	Password  = "esptest_password"
+)
+
+// A Server is a fake ESP API backed by in-memory state. The exported fields
+// hold the canned responses for the read-only endpoints and may be changed
+// between requests.
+type Server struct {
+	*httptest.Server
+
+	APIKey    string
+	APISecret string
+	Username  string
+	Password  string
+
+	// Keywords maps each term the keywords endpoint recognizes to its
+	// matches. Terms that are absent have no matches and are invalid.
+	Keywords map[string][]string
+
+	// ControlledValues is returned verbatim by the controlled values
+	// endpoint, so it uses the raw ESP shape: "batch_types" alongside one
+	// map of controlled fields per batch type. New Batches must have one
+	// of the "batch_types".
+	ControlledValues map[string]interface{}
+
+	Events             []espsdk.Event
+	FieldRestrictions  espsdk.FieldRestrictionResponse
+	TranscoderMappings espsdk.TranscoderMappingList
+
+	// RejectStaleUpdates, if set, makes a PUT of a Batch that carries an
+	// updated_at other than that of the stored Batch fail with 409
+	// Conflict, as ESP may do for a save made from an outdated copy.
+	RejectStaleUpdates bool
+
+	// Intercept, if set, is called with each authorized API request
+	// before the Server handles it, without the Server's lock held, so it
+	// may change the stored data. If it returns true the request is
+	// considered answered. Tests use it to inject failures, unusual
+	// responses or concurrent changes.
+	Intercept func(w http.ResponseWriter, r *http.Request) bool
+
+	mu            sync.Mutex
+	nextID        int
+	tokens        map[string]bool
+	refreshTokens map[string]bool
+	tokenRequests int
+	batches       []*espsdk.Batch
+	contributions map[string][]*espsdk.Contribution
+	releases      map[string][]*espsdk.Release
+}
+
+// NewServer starts a Server with default canned data and no Batches.
+func NewServer() *Server {
+	s := &Server{
+		APIKey:    APIKey,
+		APISecret: APISecret,
+		Username:  Username,
+		Password:  Password,
+		Keywords: map[string][]string{
+			"dog":    {"dog"},
+			"cat":    {"cat"},
+			"animal": {"animal"},
+		},
+		ControlledValues: map[string]interface{}{
+			"batch_types": []string{
+				"getty_creative_still",
+				"getty_creative_video",
+				"getty_editorial_still",
+				"getty_editorial_video",
+				"istock_creative_video",
+			},
+			"getty_creative_still": map[string]interface{}{
+				"collection_code": []map[string]string{
+					{"description": "AbleStock.com", "value": "ABL"},
+				},
+			},
+		},
+		Events: []espsdk.Event{
+			{Headline: "Tour de France", DateFrom: "2015-07-04", DateTo: "2015-07-26"},
+		},
+		FieldRestrictions: espsdk.FieldRestrictionResponse{
+			MaxBatchSize: 1000,
+			ModelClass:   "Contribution",
+		},
+		nextID:        1000,
+		tokens:        make(map[string]bool),
+		refreshTokens: make(map[string]bool),
+		contributions: make(map[string][]*espsdk.Contribution),
+		releases:      make(map[string][]*espsdk.Release),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// OAuthEndpoint returns the URL at which the Server issues tokens.
+func (s *Server) OAuthEndpoint() string { return s.URL + "/oauth2/token" }
+
+// Client returns an espsdk.Client configured to use the Server. Any
+// options are applied after those that point the Client at the Server.
+func (s *Server) Client(opts ...espsdk.ClientOption) espsdk.Client {
+	opts = append([]espsdk.ClientOption{
+		espsdk.WithCredentials(s.APIKey, s.APISecret, s.Username, s.Password),
+		espsdk.WithAPIRoot(s.URL),
+		espsdk.WithOAuthEndpoint(s.OAuthEndpoint()),
+	}, opts...)
+	client, err := espsdk.NewClient(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// TokenRequests reports how many tokens the Server has issued.
+func (s *Server) TokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenRequests
+}
+
+// ExpireTokens revokes every access token the Server has issued. Refresh
+// tokens remain valid.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]bool)
+}
+
+// AddBatch stores a copy of the Batch as though it had been created through
+// the API and returns the stored copy.
+func (s *Server) AddBatch(b espsdk.Batch) espsdk.Batch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addBatch(b)
+}
+
+// AddContribution stores a copy of the Contribution in the Batch named by
+// its SubmissionBatchID and returns the stored copy.
+func (s *Server) AddContribution(c espsdk.Contribution) espsdk.Contribution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addContribution(c)
+}
+
+// AddRelease stores a copy of the Release in the Batch named by its
+// SubmissionBatchID and returns the stored copy.
+func (s *Server) AddRelease(r espsdk.Release) espsdk.Release {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addRelease(r)
+}
+
+// Batch returns the stored Batch with the given ID.
+func (s *Server) Batch(id string) (espsdk.Batch, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.findBatch(id); b != nil {
+		return *b, true
+	}
+	return espsdk.Batch{}, false
+}
+
+// Batches returns every stored Batch, newest first.
+func (s *Server) Batches() []espsdk.Batch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]espsdk.Batch, len(s.batches))
+	for i, b := range s.batches {
+		out[i] = *b
+	}
+	return out
+}
+
+// Contributions returns every stored Contribution in the Batch.
+func (s *Server) Contributions(batchID string) espsdk.ContributionList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out espsdk.ContributionList
+	for _, c := range s.contributions[batchID] {
+		out = append(out, *c)
+	}
+	return out
+}
+
+// Releases returns every stored Release in the Batch.
+func (s *Server) Releases(batchID string) espsdk.ReleaseList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out espsdk.ReleaseList
+	for _, r := range s.releases[batchID] {
+		out = append(out, *r)
+	}
+	return out
+}
+
+// UpdateBatch applies fn to the stored Batch, simulating a change made on
+// the server, such as a review finishing. The Batch's UpdatedAt is advanced
+// unless fn sets it. It reports whether the Batch exists.
+func (s *Server) UpdateBatch(id string, fn func(*espsdk.Batch)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.findBatch(id)
+	if b == nil {
+		return false
+	}
+	updatedAt := b.UpdatedAt
+	fn(b)
+	if b.UpdatedAt == updatedAt {
+		b.UpdatedAt = s.now()
+	}
+	return true
+}
+
+// UpdateContribution applies fn to the stored Contribution, simulating a
+// change made on the server, such as publication. It reports whether the
+// Contribution exists.
+func (s *Server) UpdateContribution(batchID, id string, fn func(*espsdk.Contribution)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findContribution(batchID, id)
+	if c == nil {
+		return false
+	}
+	fn(c)
+	s.recount(batchID)
+	return true
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) now() *time.Time {
+	now := time.Now().UTC()
+	return &now
+}
+
+func (s *Server) addBatch(b espsdk.Batch) *espsdk.Batch {
+	if b.ID == "" {
+		b.ID = s.newID()
+	}
+	if b.Status == "" {
+		b.Status = espsdk.BatchOpen
+	}
+	if b.CreatedAt == nil {
+		b.CreatedAt = s.now()
+	}
+	b.UpdatedAt = b.CreatedAt
+	s.batches = append([]*espsdk.Batch{&b}, s.batches...)
+	return &b
+}
+
+func (s *Server) addContribution(c espsdk.Contribution) *espsdk.Contribution {
+	if c.ID == "" {
+		c.ID = s.newID()
+	}
+	if c.Status == "" {
+		c.Status = "pending"
+	}
+	if c.CreatedAt == nil {
+		c.CreatedAt = s.now()
+	}
+	c.UpdatedAt = c.CreatedAt
+	c.Submittable = submittable(c)
+	s.contributions[c.SubmissionBatchID] = append(s.contributions[c.SubmissionBatchID], &c)
+	s.recount(c.SubmissionBatchID)
+	return &c
+}
+
+func (s *Server) addRelease(r espsdk.Release) *espsdk.Release {
+	if r.ID == "" {
+		r.ID = s.newID()
+	}
+	s.releases[r.SubmissionBatchID] = append(s.releases[r.SubmissionBatchID], &r)
+	return &r
+}
+
+// sameTime reports whether a and b are both unset or the same instant.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// submittable approximates ESP's own rule: a pending Contribution with a
+// headline and a file can be submitted.
+func submittable(c espsdk.Contribution) bool {
+	return c.Status == "pending" && c.Headline != "" &&
+		(c.FileName != "" || c.ExternalFileLocation != "")
+}
+
+// recount refreshes the Batch's contribution counters.
+func (s *Server) recount(batchID string) {
+	b := s.findBatch(batchID)
+	if b == nil {
+		return
+	}
+	b.ContributionsCount = len(s.contributions[batchID])
+	b.SubmittedContributionsCount = 0
+	b.ContributionsAwaitingReviewCount = 0
+	b.ReviewedContributionsCount = 0
+	for _, c := range s.contributions[batchID] {
+		switch c.Status {
+		case "submitted":
+			b.SubmittedContributionsCount++
+			b.ContributionsAwaitingReviewCount++
+		case "pending":
+		default:
+			b.SubmittedContributionsCount++
+			b.ReviewedContributionsCount++
+		}
+	}
+}
+
+func (s *Server) findBatch(id string) *espsdk.Batch {
+	for _, b := range s.batches {
+		if b.ID == id {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) findContribution(batchID, id string) *espsdk.Contribution {
+	for _, c := range s.contributions[batchID] {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) findRelease(batchID, id string) *espsdk.Release {
+	for _, r := range s.releases[batchID] {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth2/token" {
+		s.serveToken(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Invalid or expired token")
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if s.Intercept != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if s.Intercept(w, r) {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch path := r.URL.Path; {
+	case path == espsdk.Endpoints.Keywords:
+		s.serveKeywords(w, body)
+	case path == espsdk.Endpoints.ControlledValues:
+		writeJSON(w, http.StatusOK, s.ControlledValues)
+	case path == espsdk.Endpoints.Events:
+		s.serveEvents(w, body)
+	case path == espsdk.Endpoints.FieldRestrictions:
+		writeJSON(w, http.StatusOK, s.FieldRestrictions)
+	case path == espsdk.Endpoints.TranscoderMappings:
+		writeJSON(w, http.StatusOK, s.TranscoderMappings)
+	case strings.HasPrefix(path, espsdk.Endpoints.Batches):
+		rest := strings.Trim(strings.TrimPrefix(path, espsdk.Endpoints.Batches), "/")
+		var parts []string
+		if rest != "" {
+			parts = strings.Split(rest, "/")
+		}
+		s.serveBatches(w, r, parts, body)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+	r.ParseForm()
+	form := r.PostForm
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	valid := form.Get("client_id") == s.APIKey && form.Get("client_secret") == s.APISecret
+	switch form.Get("grant_type") {
+	case "password":
+		valid = valid && form.Get("username") == s.Username && form.Get("password") == s.Password
+	case "refresh_token":
+		valid = valid && s.refreshTokens[form.Get("refresh_token")]
+	default:
+		valid = false
+	}
+	if !valid {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_client",
+			"error_description": "Invalid credentials",
+		})
+		return
+	}
+
+	s.tokenRequests++
+	token := fmt.Sprintf("esptest-token-%d", s.tokenRequests)
+	s.tokens[token] = true
+	s.refreshTokens[token+"-refresh"] = true
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token":  token,
+		"token_type":    "Bearer",
+		"expires_in":    "1800",
+		"refresh_token": token + "-refresh",
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return r.Header.Get("Api-Key") == s.APIKey && s.tokens[token]
+}
+
+// serveBatches dispatches requests below the submission_batches endpoint.
+// parts holds the path segments after the endpoint itself, e.g.
+// ["42", "contributions", "7", "submit"].
+func (s *Server) serveBatches(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case "GET":
+			s.indexBatches(w, r)
+		case "POST":
+			var b espsdk.Batch
+			if err := unwrap(body, "submission_batch", &b); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if !b.NameIsValid() || !s.batchTypeIsValid(b.SubmissionType) {
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+					"message": "Validation failed",
+					"errors":  map[string][]string{"submission_batch": {"name and type are required"}},
+				})
+				return
+			}
+			b = espsdk.Batch{
+				SubmissionName:        b.SubmissionName,
+				SubmissionType:        b.SubmissionType,
+				AssignmentID:          b.AssignmentID,
+				BatchTags:             b.BatchTags,
+				BriefID:               b.BriefID,
+				EventID:               b.EventID,
+				IstockExclusive:       b.IstockExclusive,
+				Note:                  b.Note,
+				ProfileID:             b.ProfileID,
+				SaveExtractedMetadata: b.SaveExtractedMetadata,
+				IsGetty:               strings.HasPrefix(b.SubmissionType, "getty_"),
+				IsIstock:              strings.HasPrefix(b.SubmissionType, "istock_"),
+			}
+			writeJSON(w, http.StatusCreated, s.addBatch(b))
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	batch := s.findBatch(parts[0])
+	if batch == nil {
+		writeError(w, http.StatusNotFound, "Submission batch not found")
+		return
+	}
+	if len(parts) == 1 {
+		s.serveBatch(w, r, batch, body)
+		return
+	}
+	switch parts[1] {
+	case "contributions":
+		s.serveContributions(w, r, batch.ID, parts[2:], body)
+	case "releases":
+		s.serveReleases(w, r, batch.ID, parts[2:], body)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// batchTypeIsValid reports whether the type is one of the "batch_types" in
+// ControlledValues.
+func (s *Server) batchTypeIsValid(name string) bool {
+	types, _ := s.ControlledValues["batch_types"].([]string)
+	for _, t := range types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) indexBatches(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	submissionType := r.URL.Query().Get("submission_type")
+	var items []espsdk.Batch
+	for _, b := range s.batches {
+		if status != "" && string(b.Status) != status ||
+			submissionType != "" && b.SubmissionType != submissionType {
+			continue
+		}
+		items = append(items, *b)
+	}
+	lo, hi := page(r, len(items))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items": nonNil(items[lo:hi]),
+		"meta":  map[string]int{"total_items": len(items)},
+	})
+}
+
+func (s *Server) serveBatch(w http.ResponseWriter, r *http.Request, batch *espsdk.Batch, body []byte) {
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, batch)
+	case "PUT":
+		updated := *batch
+		// Decoding reuses the slice's array, which the stored Batch
+		// must keep if the update is refused.
+		updated.BatchTags = append([]string(nil), batch.BatchTags...)
+		updated.UpdatedAt = nil
+		if err := unwrap(body, "submission_batch", &updated); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if s.RejectStaleUpdates && updated.UpdatedAt != nil && !sameTime(updated.UpdatedAt, batch.UpdatedAt) {
+			writeError(w, http.StatusConflict, "Submission batch was modified")
+			return
+		}
+		updated.ID = batch.ID
+		updated.CreatedAt = batch.CreatedAt
+		updated.UpdatedAt = s.now()
+		*batch = updated
+		writeJSON(w, http.StatusOK, batch)
+	case "DELETE":
+		for i, b := range s.batches {
+			if b == batch {
+				s.batches = append(s.batches[:i], s.batches[i+1:]...)
+				break
+			}
+		}
+		delete(s.contributions, batch.ID)
+		delete(s.releases, batch.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) serveContributions(w http.ResponseWriter, r *http.Request, batchID string, parts []string, body []byte) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case "GET":
+			items := s.contributions[batchID]
+			lo, hi := page(r, len(items))
+			var out espsdk.ContributionList
+			for _, c := range items[lo:hi] {
+				out = append(out, *c)
+			}
+			writeJSON(w, http.StatusOK, nonNil(out))
+		case "POST":
+			var c espsdk.Contribution
+			if err := unwrap(body, "contribution", &c); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			c.ID, c.Status, c.CreatedAt = "", "", nil
+			c.SubmissionBatchID = batchID
+			writeJSON(w, http.StatusCreated, s.addContribution(c))
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	c := s.findContribution(batchID, parts[0])
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Contribution not found")
+		return
+	}
+	if len(parts) == 2 && parts[1] == "submit" && r.Method == "PUT" {
+		if !c.Submittable {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"message": "Contribution cannot be submitted",
+				"errors":  map[string][]string{"contribution": {"is not submittable"}},
+			})
+			return
+		}
+		c.Status = "submitted"
+		c.Submittable = false
+		c.SubmittedAt = s.now()
+		c.UpdatedAt = c.SubmittedAt
+		s.recount(batchID)
+		writeJSON(w, http.StatusOK, c)
+		return
+	}
+	if len(parts) != 1 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, c)
+	case "PUT":
+		updated := *c
+		if err := unwrap(body, "contribution", &updated); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		updated.ID = c.ID
+		updated.SubmissionBatchID = c.SubmissionBatchID
+		updated.Status = c.Status
+		updated.CreatedAt = c.CreatedAt
+		updated.UpdatedAt = s.now()
+		updated.Submittable = submittable(updated)
+		*c = updated
+		writeJSON(w, http.StatusOK, c)
+	case "DELETE":
+		list := s.contributions[batchID]
+		for i, existing := range list {
+			if existing == c {
+				s.contributions[batchID] = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+		s.recount(batchID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) serveReleases(w http.ResponseWriter, r *http.Request, batchID string, parts []string, body []byte) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case "GET":
+			items := s.releases[batchID]
+			lo, hi := page(r, len(items))
+			var out espsdk.ReleaseList
+			for _, rel := range items[lo:hi] {
+				out = append(out, *rel)
+			}
+			writeJSON(w, http.StatusOK, nonNil(out))
+		case "POST":
+			var rel espsdk.Release
+			if err := unwrap(body, "release", &rel); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rel.ID = ""
+			rel.SubmissionBatchID = batchID
+			writeJSON(w, http.StatusCreated, s.addRelease(rel))
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	rel := s.findRelease(batchID, parts[0])
+	if rel == nil || len(parts) != 1 {
+		writeError(w, http.StatusNotFound, "Release not found")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, rel)
+	case "DELETE":
+		list := s.releases[batchID]
+		for i, existing := range list {
+			if existing == rel {
+				s.releases[batchID] = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) serveKeywords(w http.ResponseWriter, body []byte) {
+	var query struct {
+		Keywords  []string `json:"keywords"`
+		MediaType string   `json:"media_type"`
+	}
+	json.Unmarshal(body, &query)
+	matches := make(map[string][]string)
+	for _, kw := range query.Keywords {
+		matches[kw] = append([]string{}, s.Keywords[strings.ToLower(kw)]...)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keywords": matches})
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, body []byte) {
+	var query espsdk.EventQuery
+	json.Unmarshal(body, &query)
+	name := strings.ToLower(query.EventName)
+	events := []espsdk.Event{}
+	for _, e := range s.Events {
+		if strings.Contains(strings.ToLower(e.Headline), name) {
+			events = append(events, e)
+		}
+	}
+	writeJSON(w, http.StatusOK, espsdk.EventResponse{
+		Events:            events,
+		SearchInformation: map[string]interface{}{"total_count": len(events)},
+		Errors:            []string{},
+	})
+}
+
+// page returns the bounds of the requested page of n items, honoring the
+// limit and offset query parameters the way ESP does.
+func page(r *http.Request, n int) (int, int) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = n
+	}
+	lo := offset
+	if lo > n {
+		lo = n
+	}
+	hi := lo + limit
+	if hi > n {
+		hi = n
+	}
+	return lo, hi
+}
+
+// unwrap decodes a request body that may or may not be wrapped in an object
+// with a single key, as ESP accepts both forms.
+func unwrap(body []byte, key string, dest interface{}) error {
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return err
+	}
+	if inner, ok := wrapper[key]; ok && len(wrapper) == 1 {
+		return json.Unmarshal(inner, dest)
+	}
+	return json.Unmarshal(body, dest)
+}
+
+// nonNil makes empty lists encode as [] rather than null, as ESP does.
+func nonNil(v interface{}) interface{} {
+	switch list := v.(type) {
+	case []espsdk.Batch:
+		if list == nil {
+			return []espsdk.Batch{}
+		}
+	case espsdk.ContributionList:
+		if list == nil {
+			return espsdk.ContributionList{}
+		}
+	case espsdk.ReleaseList:
+		if list == nil {
+			return espsdk.ReleaseList{}
+		}
+	}
+	return v
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
@@ -0,0 +1,168 @@
+package esptest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestBatchLifecycle(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	result, err := client.CreateContext(ctx, espsdk.Batch{
+		SubmissionName: "My Photos",
+		SubmissionType: "getty_creative_still",
+	})
+	if err != nil || result.StatusCode != 201 {
+		t.Fatalf("create failed: %v %d", err, result.StatusCode)
+	}
+	created, err := espsdk.Batch{}.Unmarshal(result.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contribution := espsdk.Contribution{
+		SubmissionBatchID: created.ID,
+		Headline:          "A dog",
+		FileName:          "IMG_0001.JPG",
+	}
+	if _, err := contribution.CreateAndSubmitContext(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	batches, err := espsdk.Batch{}.IndexContext(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches.TotalItems != 1 || batches.Last().SubmittedContributionsCount != 1 {
+		t.Errorf("unexpected index: %+v", batches)
+	}
+	contributions := srv.Contributions(created.ID)
+	if len(contributions) != 1 || contributions[0].Status != "submitted" {
+		t.Errorf("unexpected contributions: %+v", contributions)
+	}
+}
+
+func TestLegacyMethods(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still"})
+	srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Headline: "A dog", FileName: "dog.jpg"})
+
+	if _, err := espsdk.SubmitLastPhoto(client); err != nil {
+		t.Fatal(err)
+	}
+	batches, err := espsdk.Batch{}.Index(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches.Last().SubmittedContributionsCount != 1 {
+		t.Errorf("unexpected index: %+v", batches)
+	}
+	if n := srv.TokenRequests(); n != 1 {
+		t.Errorf("got %d token requests, want 1", n)
+	}
+}
+
+func TestSubmitRejectsUnsubmittableContribution(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_creative_still"})
+	c := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID})
+
+	_, err := c.SubmitContext(context.Background(), srv.Client())
+	if !espsdk.IsValidation(err) {
+		t.Errorf("got %v, want a validation error", err)
+	}
+}
+
+func TestMissingBatchIsNotFound(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+
+	_, err := espsdk.Release{}.IndexContext(context.Background(), srv.Client(), "404")
+	if !espsdk.IsNotFound(err) {
+		t.Errorf("got %v, want not found", err)
+	}
+}
+
+func TestBadCredentialsAreUnauthorized(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	srv.Password = "changed"
+
+	_, err := client.GetControlledValuesContext(context.Background())
+	if !espsdk.IsUnauthorized(err) {
+		t.Errorf("got %v, want unauthorized", err)
+	}
+}
+
+func TestReadOnlyEndpoints(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	keywords, err := client.ValidateKeywordsContext(ctx, []string{"dog", "zzyzx"}, "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kw := range keywords {
+		if kw.Valid != (kw.Term == "dog") {
+			t.Errorf("unexpected validity for %q", kw.Term)
+		}
+	}
+
+	cv, err := client.GetControlledValuesContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cv.BatchTypes) != 5 || len(cv.ControlledFields["getty_creative_still"]["collection_code"]) != 1 {
+		t.Errorf("unexpected controlled values: %+v", cv)
+	}
+
+	events, err := client.GetEventsContext(ctx, espsdk.EventQuery{EventName: "tour"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Events) != 1 {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	restrictions, err := client.GetFieldRestrictionsContext(ctx, espsdk.FieldRestrictionQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restrictions.MaxBatchSize != 1000 {
+		t.Errorf("unexpected field restrictions: %+v", restrictions)
+	}
+
+	if srv.TokenRequests() != 1 {
+		t.Errorf("token was requested %d times, want 1", srv.TokenRequests())
+	}
+}
+
+func TestRevokedTokenIsReplaced(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	if _, err := client.GetControlledValuesContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	srv.ExpireTokens()
+	if _, err := client.GetControlledValuesContext(ctx); err != nil {
+		t.Fatalf("the client should have reauthenticated: %v", err)
+	}
+	if srv.TokenRequests() != 2 {
+		t.Errorf("token was requested %d times, want 2", srv.TokenRequests())
+	}
+}
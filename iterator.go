@@ -0,0 +1,300 @@
+package espsdk
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// DefaultPageSize is the number of items an iterator requests per page when
+// its PageSize is not set.
+const DefaultPageSize = 50
+
+// A pageQuery is the path of one page of an Index, optionally narrowed by
+// filter parameters.
+type pageQuery struct {
+	path   string
+	limit  int
+	offset int
+	params url.Values
+}
+
+// Path returns the Index path with the paging and filter parameters
+// appended.
+func (q pageQuery) Path() string {
+	path := fmt.Sprintf("%s?limit=%d&offset=%d", q.path, q.limit, q.offset)
+	if len(q.params) > 0 {
+		path += "&" + q.params.Encode()
+	}
+	return path
+}
+
+// seenIDs records the IDs an iterator has returned, so that a page that only
+// repeats them, as when ESP ignores the limit and offset, ends the walk.
+type seenIDs map[string]bool
+
+// add records id and reports whether it had not been seen before. Items
+// without an ID are always new.
+func (s seenIDs) add(id string) bool {
+	if id == "" {
+		return true
+	}
+	if s[id] {
+		return false
+	}
+	s[id] = true
+	return true
+}
+
+// pageSize returns the configured page size or DefaultPageSize.
+func pageSize(size int) int {
+	if size <= 0 {
+		return DefaultPageSize
+	}
+	return size
+}
+
+// A BatchIterator walks every Batch for the account, requesting pages
+// lazily as they are needed. PageSize and Offset may be changed before the
+// first call to Next.
+//
+//	it := espsdk.NewBatchIterator(client)
+//	for it.Next(ctx) {
+//		fmt.Println(it.Batch().SubmissionName)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type BatchIterator struct {
+	PageSize int
+	Offset   int
+
+	client     ContextClient
+	params     url.Values
+	page       []Batch
+	seen       seenIDs
+	current    Batch
+	totalItems int
+	done       bool
+	err        error
+}
+
+// NewBatchIterator returns a BatchIterator that starts at the first Batch.
+func NewBatchIterator(client ContextClient) *BatchIterator {
+	return &BatchIterator{client: client, seen: seenIDs{}}
+}
+
+// Next advances to the next Batch, fetching another page if necessary. It
+// returns false when there are no more Batches or a request fails.
+func (it *BatchIterator) Next(ctx context.Context) bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil || !it.fetch(ctx) {
+			return false
+		}
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *BatchIterator) fetch(ctx context.Context) bool {
+	desc := "BatchIterator.Next"
+	size := pageSize(it.PageSize)
+	result, err := it.client.GetContext(ctx, pageQuery{Endpoints.Batches, size, it.Offset, it.params})
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		it.err = err
+		return false
+	}
+	result.Log().Debug(desc)
+	batchList, err := BatchList{}.Unmarshal(result.Payload)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = nil
+	for _, b := range batchList.Items {
+		if it.seen.add(b.ID) {
+			it.page = append(it.page, b)
+		}
+	}
+	it.totalItems = batchList.TotalItems
+	it.Offset += len(batchList.Items)
+	// ESP omits the metadata on some responses, so the total is only
+	// trusted when it has been reported.
+	it.done = len(batchList.Items) < size || len(it.page) == 0 ||
+		batchList.TotalItems > 0 && it.Offset >= batchList.TotalItems
+	return len(it.page) > 0
+}
+
+// Batch returns the Batch at the current position of the iterator.
+func (it *BatchIterator) Batch() Batch { return it.current }
+
+// TotalItems returns the number of Batches the ESP API reported for the
+// account in the most recently fetched page.
+func (it *BatchIterator) TotalItems() int { return it.totalItems }
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *BatchIterator) Err() error { return it.err }
+
+// All drains the iterator and returns every remaining Batch.
+func (it *BatchIterator) All(ctx context.Context) ([]Batch, error) {
+	var batches []Batch
+	for it.Next(ctx) {
+		batches = append(batches, it.Batch())
+	}
+	return batches, it.Err()
+}
+
+// A ContributionIterator walks every Contribution in a Batch, requesting
+// pages lazily as they are needed. PageSize and Offset may be changed before
+// the first call to Next.
+type ContributionIterator struct {
+	PageSize int
+	Offset   int
+
+	client  ContextClient
+	batchID string
+	page    ContributionList
+	seen    seenIDs
+	current Contribution
+	done    bool
+	err     error
+}
+
+// NewContributionIterator returns a ContributionIterator that starts at the
+// first Contribution in the Batch.
+func NewContributionIterator(client ContextClient, batchID string) *ContributionIterator {
+	return &ContributionIterator{client: client, batchID: batchID, seen: seenIDs{}}
+}
+
+// Next advances to the next Contribution, fetching another page if
+// necessary. It returns false when there are no more Contributions or a
+// request fails.
+func (it *ContributionIterator) Next(ctx context.Context) bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil || !it.fetch(ctx) {
+			return false
+		}
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *ContributionIterator) fetch(ctx context.Context) bool {
+	desc := "ContributionIterator.Next"
+	size := pageSize(it.PageSize)
+	path := Contribution{SubmissionBatchID: it.batchID}.Path()
+	result, err := it.client.GetContext(ctx, pageQuery{path, size, it.Offset, nil})
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		it.err = err
+		return false
+	}
+	result.Log().Debug(desc)
+	contributionList, err := ContributionList{}.Unmarshal(result.Payload)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = nil
+	for _, c := range contributionList {
+		if it.seen.add(c.ID) {
+			it.page = append(it.page, c)
+		}
+	}
+	it.Offset += len(contributionList)
+	it.done = len(contributionList) < size || len(it.page) == 0
+	return len(it.page) > 0
+}
+
+// Contribution returns the Contribution at the current position of the
+// iterator.
+func (it *ContributionIterator) Contribution() Contribution { return it.current }
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *ContributionIterator) Err() error { return it.err }
+
+// All drains the iterator and returns every remaining Contribution.
+func (it *ContributionIterator) All(ctx context.Context) (ContributionList, error) {
+	var contributions ContributionList
+	for it.Next(ctx) {
+		contributions = append(contributions, it.Contribution())
+	}
+	return contributions, it.Err()
+}
+
+// A ReleaseIterator walks every Release in a Batch, requesting pages lazily
+// as they are needed. PageSize and Offset may be changed before the first
+// call to Next.
+type ReleaseIterator struct {
+	PageSize int
+	Offset   int
+
+	client  ContextClient
+	batchID string
+	page    ReleaseList
+	seen    seenIDs
+	current Release
+	done    bool
+	err     error
+}
+
+// NewReleaseIterator returns a ReleaseIterator that starts at the first
+// Release in the Batch.
+func NewReleaseIterator(client ContextClient, batchID string) *ReleaseIterator {
+	return &ReleaseIterator{client: client, batchID: batchID, seen: seenIDs{}}
+}
+
+// Next advances to the next Release, fetching another page if necessary. It
+// returns false when there are no more Releases or a request fails.
+func (it *ReleaseIterator) Next(ctx context.Context) bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil || !it.fetch(ctx) {
+			return false
+		}
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *ReleaseIterator) fetch(ctx context.Context) bool {
+	desc := "ReleaseIterator.Next"
+	size := pageSize(it.PageSize)
+	path := Release{SubmissionBatchID: it.batchID}.Path()
+	result, err := it.client.GetContext(ctx, pageQuery{path, size, it.Offset, nil})
+	if err = checkResponse(result, err); err != nil {
+		result.Log().Error(desc)
+		it.err = err
+		return false
+	}
+	result.Log().Debug(desc)
+	releaseList, err := ReleaseList{}.Unmarshal(result.Payload)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = nil
+	for _, r := range releaseList {
+		if it.seen.add(r.ID) {
+			it.page = append(it.page, r)
+		}
+	}
+	it.Offset += len(releaseList)
+	it.done = len(releaseList) < size || len(it.page) == 0
+	return len(it.page) > 0
+}
+
+// Release returns the Release at the current position of the iterator.
+func (it *ReleaseIterator) Release() Release { return it.current }
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *ReleaseIterator) Err() error { return it.err }
+
+// All drains the iterator and returns every remaining Release.
+func (it *ReleaseIterator) All(ctx context.Context) (ReleaseList, error) {
+	var releases ReleaseList
+	for it.Next(ctx) {
+		releases = append(releases, it.Release())
+	}
+	return releases, it.Err()
+}
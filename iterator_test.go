@@ -0,0 +1,135 @@
+package espsdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestBatchIteratorWalksEveryPage(t *testing.T) {
+	var pages int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		list := BatchList{batchListMetadata: batchListMetadata{TotalItems: 7}}
+		for i := offset; i < offset+limit && i < 7; i++ {
+			list.Items = append(list.Items, Batch{ID: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+
+	it := NewBatchIterator(client)
+	it.PageSize = 3
+	batches, err := it.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 7 || batches[6].ID != "6" {
+		t.Errorf("got %v", batches)
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+	if it.TotalItems() != 7 {
+		t.Errorf("got total %d", it.TotalItems())
+	}
+}
+
+func TestBatchIteratorWithoutMetadata(t *testing.T) {
+	var pages int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var items []Batch
+		for i := offset; i < offset+2 && i < 5; i++ {
+			items = append(items, Batch{ID: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	})
+
+	it := NewBatchIterator(client)
+	it.PageSize = 2
+	batches, err := it.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 5 || pages != 3 {
+		t.Errorf("got %d Batches from %d pages, want 5 from 3", len(batches), pages)
+	}
+}
+
+func TestContributionIteratorHonorsOffset(t *testing.T) {
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != (Contribution{SubmissionBatchID: "9"}).Path() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var list ContributionList
+		for i := offset; i < 5; i++ {
+			list = append(list, Contribution{ID: fmt.Sprint(i)})
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+
+	it := NewContributionIterator(client, "9")
+	it.Offset = 2
+	contributions, err := it.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contributions) != 3 || contributions[0].ID != "2" {
+		t.Errorf("got %v", contributions)
+	}
+}
+
+func TestIteratorsStopWhenPagingIsIgnored(t *testing.T) {
+	var pages int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		var items []map[string]string
+		for i := 0; i < 60; i++ {
+			items = append(items, map[string]string{"id": strconv.Itoa(i)})
+		}
+		if r.URL.Path == Endpoints.Batches {
+			json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+			return
+		}
+		json.NewEncoder(w).Encode(items)
+	})
+
+	ctx := context.Background()
+	batches, err := NewBatchIterator(client).All(ctx)
+	if err != nil || len(batches) != 60 {
+		t.Errorf("got %d Batches, %v", len(batches), err)
+	}
+	contributions, err := NewContributionIterator(client, "9").All(ctx)
+	if err != nil || len(contributions) != 60 {
+		t.Errorf("got %d Contributions, %v", len(contributions), err)
+	}
+	releases, err := NewReleaseIterator(client, "9").All(ctx)
+	if err != nil || len(releases) != 60 {
+		t.Errorf("got %d Releases, %v", len(releases), err)
+	}
+	if pages != 6 {
+		t.Errorf("fetched %d pages, want 6", pages)
+	}
+}
+
+func TestReleaseIteratorStopsOnError(t *testing.T) {
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	it := NewReleaseIterator(client, "9")
+	if it.Next(context.Background()) {
+		t.Errorf("Next should fail")
+	}
+	if !IsUnauthorized(it.Err()) {
+		t.Errorf("got %v", it.Err())
+	}
+}
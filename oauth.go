@@ -0,0 +1,187 @@
+package espsdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dysolution/sleepwalker"
+)
+
+// A Token is an OAuth access token issued by the Getty Images API.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Valid reports whether the Token can still be used to authenticate.
+func (t Token) Valid() bool {
+	return t.AccessToken != "" && time.Now().Before(t.ExpiresAt)
+}
+
+// tokenRefreshWindow is how long before its expiry a Token is replaced, so
+// that requests in flight do not race the expiry.
+const tokenRefreshWindow = 2 * time.Minute
+
+// expiresWithin reports whether the Token is missing or expires in less
+// than d.
+func (t Token) expiresWithin(d time.Duration) bool {
+	return t.AccessToken == "" || time.Now().Add(d).After(t.ExpiresAt)
+}
+
+// tokenKey identifies the account a Client authenticates as, so that one
+// TokenStore can hold tokens for several accounts and environments.
+func (c Client) tokenKey() string {
+	return c.OAuthEndpoint + "|" + c.credentials.APIKey + "|" + c.credentials.Username
+}
+
+// accessToken returns a usable access token from the Client's TokenStore,
+// obtaining and saving a new one when the stored Token is missing or close
+// to expiry.
+func (c Client) accessToken(ctx context.Context) (string, error) {
+	if c.TokenStore == nil {
+		token, err := c.requestToken(ctx, "")
+		return token.AccessToken, err
+	}
+	key := c.tokenKey()
+	if token, err := c.TokenStore.Load(key); err == nil && !token.expiresWithin(tokenRefreshWindow) {
+		return token.AccessToken, nil
+	}
+
+	// Only one goroutine per Client refreshes; the others wait and then
+	// use what it saved.
+	if c.tokenMu != nil {
+		c.tokenMu.Lock()
+		defer c.tokenMu.Unlock()
+	}
+	stored, err := c.TokenStore.Load(key)
+	if err != nil {
+		Log.WithField("error", err).Warn("Client.accessToken")
+	}
+	if err == nil && !stored.expiresWithin(tokenRefreshWindow) {
+		return stored.AccessToken, nil
+	}
+
+	token, err := c.requestToken(ctx, stored.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	if err := c.TokenStore.Save(key, token); err != nil {
+		Log.WithField("error", err).Warn("Client.accessToken")
+	}
+	return token.AccessToken, nil
+}
+
+// invalidateToken removes the access token from the TokenStore if it is
+// still the stored one, e.g. after ESP has rejected it.
+func (c Client) invalidateToken(accessToken string) {
+	if c.TokenStore == nil {
+		return
+	}
+	if c.tokenMu != nil {
+		c.tokenMu.Lock()
+		defer c.tokenMu.Unlock()
+	}
+	key := c.tokenKey()
+	if stored, err := c.TokenStore.Load(key); err == nil && stored.AccessToken == accessToken {
+		c.TokenStore.Save(key, Token{RefreshToken: stored.RefreshToken})
+	}
+}
+
+// requestToken obtains a new Token, using the refresh grant when a refresh
+// token is available and falling back to the password grant.
+func (c Client) requestToken(ctx context.Context, refreshToken string) (Token, error) {
+	if refreshToken != "" {
+		token, err := c.postToken(ctx, url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {c.credentials.APIKey},
+			"client_secret": {c.credentials.APISecret},
+			"refresh_token": {refreshToken},
+		})
+		if err == nil {
+			if token.RefreshToken == "" {
+				token.RefreshToken = refreshToken
+			}
+			return token, nil
+		}
+		if ctx.Err() != nil {
+			return Token{}, err
+		}
+	}
+	return c.postToken(ctx, url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {c.credentials.APIKey},
+		"client_secret": {c.credentials.APISecret},
+		"username":      {c.credentials.Username},
+		"password":      {c.credentials.Password},
+	})
+}
+
+// postToken sends a token request to the OAuth endpoint.
+func (c Client) postToken(ctx context.Context, form url.Values) (Token, error) {
+	desc := "Client.requestToken"
+	req, err := http.NewRequestWithContext(ctx, "POST", c.OAuthEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Token{}, err
+	}
+	result := Result{
+		Result:   sleepwalker.Result{StatusCode: resp.StatusCode, Payload: payload},
+		Verb:     "POST",
+		Path:     c.OAuthEndpoint,
+		Header:   resp.Header,
+		Duration: time.Since(start),
+		logger:   c.logger,
+	}
+	if err = checkResponse(result, nil); err != nil {
+		result.Log().WithField("grant_type", form.Get("grant_type")).Error(desc)
+		return Token{}, err
+	}
+	result.Log().WithField("grant_type", form.Get("grant_type")).Debug(desc)
+	return parseToken(payload, start)
+}
+
+// parseToken reads an OAuth token response. The Getty Images API sends
+// expires_in as a string, so both strings and numbers are accepted.
+func parseToken(payload []byte, issuedAt time.Time) (Token, error) {
+	var body struct {
+		AccessToken  string          `json:"access_token"`
+		RefreshToken string          `json:"refresh_token"`
+		TokenType    string          `json:"token_type"`
+		ExpiresIn    json.RawMessage `json:"expires_in"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return Token{}, err
+	}
+	seconds, err := strconv.Atoi(strings.Trim(string(body.ExpiresIn), `"`))
+	if err != nil {
+		seconds = 0
+	}
+	return Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		TokenType:    body.TokenType,
+		ExpiresAt:    issuedAt.Add(time.Duration(seconds) * time.Second),
+	}, nil
+}
@@ -0,0 +1,209 @@
+package espsdk
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/dysolution/sleepwalker"
+)
+
+// DefaultUserAgent identifies the SDK to the ESP API.
+const DefaultUserAgent = "espsdk (+https://github.com/dysolution/espsdk)"
+
+// A ClientOption configures a Client built by NewClient.
+type ClientOption func(*Client) error
+
+// NewClient provides a client for communicating with the ESP REST API,
+// configured by the provided options. Unless overridden, the Client talks to
+// ProdAPI, authenticates at OAuthEndpoint, logs to Log and uses
+// DefaultRetryPolicy and a MemoryTokenStore. The options apply to every
+// request the Client sends, including those of the methods that take a
+// sleepwalker.RESTClient, such as Batch.Index.
+//
+//	client, err := espsdk.NewClient(
+//		espsdk.WithCredentials(key, secret, username, password),
+//		espsdk.WithAPIRoot(espsdk.SandboxAPI),
+//		espsdk.WithTimeout(30*time.Second),
+//	)
+func NewClient(opts ...ClientOption) (Client, error) {
+	c, err := newClient(opts...)
+	if err != nil {
+		return Client{}, err
+	}
+	if c.credentials.APIKey == "" || c.credentials.APISecret == "" {
+		return Client{}, errors.New("espsdk: an API key and secret are required")
+	}
+	return c, nil
+}
+
+// newClient applies the options without checking that the result is
+// usable, as GetClient never has.
+func newClient(opts ...ClientOption) (Client, error) {
+	c := Client{
+		OAuthEndpoint: OAuthEndpoint,
+		RetryPolicy:   DefaultRetryPolicy,
+		TokenStore:    NewMemoryTokenStore(),
+		UserAgent:     DefaultUserAgent,
+		apiRoot:       ProdAPI,
+		logger:        Log,
+		tokenMu:       &sync.Mutex{},
+		knownTypes:    newSubmissionTypeSet(SubmissionTypes()...),
+	}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return Client{}, err
+		}
+	}
+
+	credentials := c.credentials
+	c.Client = sleepwalker.GetClient(&sleepwalker.Config{
+		Credentials:   &credentials,
+		OAuthEndpoint: c.OAuthEndpoint,
+		APIRoot:       c.apiRoot,
+		Logger:        c.logger,
+	})
+	return c, nil
+}
+
+// WithCredentials sets the API key and secret of the application and the
+// username and password of the ESP account.
+func WithCredentials(key, secret, username, password string) ClientOption {
+	return func(c *Client) error {
+		c.credentials = sleepwalker.Credentials{
+			APIKey:    key,
+			APISecret: secret,
+			Username:  username,
+			Password:  password,
+		}
+		return nil
+	}
+}
+
+// WithAPIRoot sets the root URL of the ESP API, such as ProdAPI or
+// SandboxAPI.
+func WithAPIRoot(apiRoot string) ClientOption {
+	return func(c *Client) error {
+		c.apiRoot = apiRoot
+		return nil
+	}
+}
+
+// WithOAuthEndpoint sets the URL from which OAuth tokens are obtained.
+func WithOAuthEndpoint(endpoint string) ClientOption {
+	return func(c *Client) error {
+		c.OAuthEndpoint = endpoint
+		return nil
+	}
+}
+
+// WithLogger sets the logger that receives the Client's request logs.
+func WithLogger(log *logrus.Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = log
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http.Client used for requests. Options that
+// change timeouts, proxies or TLS settings and are given after it modify a
+// copy, never the provided http.Client itself.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) error {
+		c.HTTPClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for requests.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) error {
+		c.ownHTTPClient().Transport = transport
+		return nil
+	}
+}
+
+// WithTimeout limits the time each HTTP request may take, including
+// reading the response. Retries each get their own timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.ownHTTPClient().Timeout = timeout
+		return nil
+	}
+}
+
+// WithProxy sends requests through the proxy at the given URL instead of
+// the one named by the environment.
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		transport, err := c.ownTransport()
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithTLS verifies servers according to the TLSOptions.
+func WithTLS(o TLSOptions) ClientOption {
+	return func(c *Client) error { return c.ConfigureTLS(o) }
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how requests recover from transient failures.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.RetryPolicy = p
+		return nil
+	}
+}
+
+// WithTokenStore sets where OAuth tokens are kept between requests.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Client) error {
+		c.TokenStore = store
+		return nil
+	}
+}
+
+// ownHTTPClient replaces the Client's HTTPClient with a copy that options
+// may modify without affecting http.DefaultClient or a caller's http.Client.
+func (c *Client) ownHTTPClient() *http.Client {
+	httpClient := &http.Client{}
+	if c.HTTPClient != nil {
+		*httpClient = *c.HTTPClient
+	}
+	c.HTTPClient = httpClient
+	return httpClient
+}
+
+// ownTransport does the same as ownHTTPClient for the http.Transport.
+func (c *Client) ownTransport() (*http.Transport, error) {
+	httpClient := c.ownHTTPClient()
+	var transport *http.Transport
+	switch t := httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, errors.New("espsdk: the configured transport is not an *http.Transport")
+	}
+	httpClient.Transport = transport
+	return transport, nil
+}
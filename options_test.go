@@ -0,0 +1,97 @@
+package espsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewClientRequiresCredentials(t *testing.T) {
+	if _, err := NewClient(WithAPIRoot(SandboxAPI)); err == nil {
+		t.Errorf("NewClient should reject a client without credentials")
+	}
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	c, err := NewClient(WithCredentials("key", "secret", "user", "pass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.apiRoot != ProdAPI || c.OAuthEndpoint != OAuthEndpoint || c.UserAgent != DefaultUserAgent {
+		t.Errorf("unexpected defaults: %+v", c)
+	}
+	if c.RetryPolicy.MaxAttempts != DefaultRetryPolicy.MaxAttempts || c.TokenStore == nil {
+		t.Errorf("unexpected defaults: %+v", c)
+	}
+}
+
+func TestOptionsDoNotModifyCallersHTTPClient(t *testing.T) {
+	shared := &http.Client{Timeout: time.Minute}
+	c, err := NewClient(
+		WithCredentials("key", "secret", "user", "pass"),
+		WithHTTPClient(shared),
+		WithTimeout(time.Second),
+		WithProxy("http://proxy.example.com:3128"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared.Timeout != time.Minute || shared.Transport != nil {
+		t.Errorf("the caller's http.Client was modified: %+v", shared)
+	}
+	if c.HTTPClient.Timeout != time.Second {
+		t.Errorf("got timeout %v", c.HTTPClient.Timeout)
+	}
+	proxy, err := c.HTTPClient.Transport.(*http.Transport).Proxy(&http.Request{URL: &url.URL{Scheme: "https"}})
+	if err != nil || proxy.Host != "proxy.example.com:3128" {
+		t.Errorf("got proxy %v, %v", proxy, err)
+	}
+}
+
+func TestProxyRequiresHTTPTransport(t *testing.T) {
+	_, err := NewClient(
+		WithCredentials("key", "secret", "user", "pass"),
+		WithTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)),
+		WithProxy("http://proxy.example.com:3128"),
+	)
+	if err == nil {
+		t.Errorf("a proxy cannot be set on a custom RoundTripper")
+	}
+}
+
+func TestUserAgentIsSent(t *testing.T) {
+	var userAgents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+		w.Write([]byte(`{"access_token": "t", "expires_in": "60"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(
+		WithCredentials("key", "secret", "user", "pass"),
+		WithAPIRoot(srv.URL),
+		WithOAuthEndpoint(srv.URL+"/oauth2/token"),
+		WithUserAgent("nightly-uploader/1.0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.GetTranscoderMappingsContext(context.Background())
+	Contribution{SubmissionBatchID: "1", ID: "2"}.Submit(c)
+	if len(userAgents) < 3 {
+		t.Errorf("got user agents %v", userAgents)
+	}
+	for _, ua := range userAgents {
+		if ua != "nightly-uploader/1.0" {
+			t.Errorf("got user agents %v", userAgents)
+			break
+		}
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
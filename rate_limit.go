@@ -0,0 +1,126 @@
+package espsdk
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The endpoint groups a RateLimiter can limit separately. ESP throttles
+// keyword lookups and submission batch operations independently of the
+// rest of the API.
+const (
+	GroupDefault           = "default"
+	GroupKeywords          = "keywords"
+	GroupSubmissionBatches = "submission_batches"
+)
+
+// EndpointGroup returns the rate limiting group of an API path.
+func EndpointGroup(path string) string {
+	switch {
+	case strings.HasPrefix(path, Endpoints.Keywords):
+		return GroupKeywords
+	case strings.HasPrefix(path, Endpoints.Batches):
+		return GroupSubmissionBatches
+	}
+	return GroupDefault
+}
+
+// A RateLimit allows Rate requests per second on average, with bursts of up
+// to Burst requests.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// A RateLimiter spaces out the requests of every Client it is shared by,
+// using one token bucket per endpoint group. Callers that must wait are
+// served in the order in which they arrived.
+type RateLimiter struct {
+	buckets map[string]*bucket
+}
+
+// NewRateLimiter returns a RateLimiter with the given limit for each
+// endpoint group. Groups without a limit of their own use the GroupDefault
+// limit; if there is none, they are not limited.
+func NewRateLimiter(limits map[string]RateLimit) *RateLimiter {
+	l := &RateLimiter{buckets: make(map[string]*bucket)}
+	for group, limit := range limits {
+		if limit.Rate <= 0 {
+			continue
+		}
+		burst := float64(limit.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		l.buckets[group] = &bucket{rate: limit.Rate, burst: burst, tokens: burst}
+	}
+	return l
+}
+
+// Wait blocks until a request to the path may be sent or ctx is done, and
+// returns how long it waited.
+func (l *RateLimiter) Wait(ctx context.Context, path string) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	b, ok := l.buckets[EndpointGroup(path)]
+	if !ok {
+		if b, ok = l.buckets[GroupDefault]; !ok {
+			return 0, nil
+		}
+	}
+
+	wait := b.reserve(time.Now())
+	if err := sleep(ctx, wait); err != nil {
+		b.cancel()
+		return wait, err
+	}
+	return wait, nil
+}
+
+// A bucket is a token bucket that hands out tokens in advance: a caller
+// that finds it empty takes a token anyway, driving the balance negative,
+// and waits until the refill would have covered it. Each caller's wait is
+// therefore fixed at the moment it arrives, which keeps them in order.
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a token whose caller gave up waiting for it.
+func (b *bucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+}
+
+// WithRateLimiter makes the Client wait for the RateLimiter before each
+// request. Share one RateLimiter between Clients that use the same account.
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(c *Client) error {
+		c.RateLimiter = l
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package espsdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEndpointGroup(t *testing.T) {
+	for path, want := range map[string]string{
+		Endpoints.Keywords:                          GroupKeywords,
+		Batch{ID: "1"}.Path():                       GroupSubmissionBatches,
+		Contribution{SubmissionBatchID: "1"}.Path(): GroupSubmissionBatches,
+		Endpoints.Events:                            GroupDefault,
+	} {
+		if got := EndpointGroup(path); got != want {
+			t.Errorf("%s: got %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	l := NewRateLimiter(map[string]RateLimit{
+		GroupKeywords: {Rate: 20, Burst: 2},
+	})
+	ctx := context.Background()
+
+	start := time.Now()
+	var mu sync.Mutex
+	var waits []time.Duration
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait, err := l.Wait(ctx, Endpoints.Keywords)
+			if err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			waits = append(waits, wait)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Two requests fit in the burst; the other two wait 50ms and 100ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("4 requests at 20/s with a burst of 2 took only %v", elapsed)
+	}
+	var zero int
+	for _, w := range waits {
+		if w == 0 {
+			zero++
+		}
+	}
+	if zero != 2 {
+		t.Errorf("got waits %v, want two without delay", waits)
+	}
+
+	if wait, _ := l.Wait(ctx, Endpoints.Events); wait != 0 {
+		t.Errorf("groups without a limit should not wait, got %v", wait)
+	}
+}
+
+func TestRateLimiterHonorsContext(t *testing.T) {
+	l := NewRateLimiter(map[string]RateLimit{GroupDefault: {Rate: 0.1}})
+	l.Wait(context.Background(), Endpoints.Events)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Wait(ctx, Endpoints.Events); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestResultReportsRateLimitWait(t *testing.T) {
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	client.RateLimiter = NewRateLimiter(map[string]RateLimit{GroupDefault: {Rate: 50}})
+	ctx := context.Background()
+
+	first, err := client.GetPathContext(ctx, Endpoints.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.GetPathContext(ctx, Endpoints.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.RateLimitWait != 0 || second.RateLimitWait == 0 {
+		t.Errorf("got waits %v and %v", first.RateLimitWait, second.RateLimitWait)
+	}
+}
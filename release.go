@@ -1,6 +1,7 @@
 package espsdk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -26,21 +27,39 @@ type Release struct {
 
 // Index requests a list of all Releases associated with the specified
 // Submission Batch.
-func (r Release) Index(client sleepwalker.RESTClient, batchID string) ReleaseList {
+func (r Release) Index(client sleepwalker.RESTClient, batchID string) (ReleaseList, error) {
+	return r.IndexContext(context.Background(), contextClient(client), batchID)
+}
+
+// IndexContext is like Index but sends the request with the provided
+// context.
+func (r Release) IndexContext(ctx context.Context, client ContextClient, batchID string) (ReleaseList, error) {
 	desc := "Release.Index"
 	r.SubmissionBatchID = batchID
-	result, err := client.Get(r)
-	if err != nil {
+	result, err := client.GetContext(ctx, r)
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
-		return ReleaseList{}
+		return ReleaseList{}, err
+	}
+	result.Log().Info(desc)
+	return ReleaseList{}.Unmarshal(result.Payload)
+}
+
+// Create asks ESP to add the Release to the Batch named by its
+// SubmissionBatchID, returning the Release as saved.
+func (r Release) Create(ctx context.Context, client ContextClient) (*Release, error) {
+	desc := "Release.Create"
+	if r.SubmissionBatchID == "" {
+		return nil, ErrMissingID
 	}
-	if result.StatusCode == 404 {
+	r.ID = ""
+	result, err := client.CreateContext(ctx, r)
+	if err = checkResponse(result, err); err != nil {
 		result.Log().Error(desc)
-		return ReleaseList{}
+		return nil, err
 	}
 	result.Log().Info(desc)
-	releaseList, _ := ReleaseList{}.Unmarshal(result.Payload)
-	return releaseList
+	return Release{}.Unmarshal(result.Payload)
 }
 
 // Path returns the path for the contribution.
@@ -0,0 +1,139 @@
+package espsdk
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// A RetryPolicy determines whether and when a Client repeats a request that
+// failed because of a transport error or a response indicating that ESP is
+// throttling or temporarily unavailable.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a request is sent,
+	// including the first. Zero or one disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. The delay doubles
+	// with each attempt, up to MaxBackoff, and a random jitter of up to half
+	// the delay is subtracted so that concurrent callers spread out.
+	InitialBackoff time.Duration
+
+	// MaxBackoff, if positive, is the longest delay between attempts,
+	// including one asked for by a Retry-After header.
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent allows POST requests to be retried. Because a
+	// failed POST may still have created an object, this is off by default.
+	RetryNonIdempotent bool
+
+	// RetryableStatus lists the status codes that are retried. If nil,
+	// DefaultRetryableStatus is used.
+	RetryableStatus []int
+}
+
+// DefaultRetryableStatus are the status codes with which ESP signals that a
+// request may succeed if repeated.
+var DefaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryPolicy is the RetryPolicy used by clients from GetClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+}
+
+// NoRetries is a RetryPolicy that sends every request exactly once.
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+// shouldRetry reports whether a request that produced the given result and
+// error may be sent again.
+func (p RetryPolicy) shouldRetry(verb string, result Result, err error) bool {
+	if verb == "POST" && !p.RetryNonIdempotent {
+		return false
+	}
+	status := result.StatusCode
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+		}
+		status = apiErr.StatusCode
+	}
+	statuses := p.RetryableStatus
+	if statuses == nil {
+		statuses = DefaultRetryableStatus
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before the given retry, where the first
+// retry is attempt 1. A Retry-After header in the response takes
+// precedence over the computed backoff, up to MaxBackoff.
+func (p RetryPolicy) delay(attempt int, result Result) time.Duration {
+	if wait, ok := retryAfter(result.Header); ok {
+		if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+			wait = p.MaxBackoff
+		}
+		return wait
+	}
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff - time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+// retryAfter parses a Retry-After header, which may be either a number of
+// seconds or an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(time.Now())
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package espsdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetriesHonorRetryAfter(t *testing.T) {
+	var calls int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"items": [], "meta": {"total_items": 0}}`))
+	})
+	client.RetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}
+
+	if _, err := (Batch{}).IndexContext(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestRetriesGiveUpAfterMaxAttempts(t *testing.T) {
+	var calls int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.RetryPolicy = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+
+	_, err := client.GetControlledValuesContext(context.Background())
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != 503 {
+		t.Errorf("got %v, want a 503", err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestPostIsNotRetriedByDefault(t *testing.T) {
+	var calls int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.RetryPolicy = RetryPolicy{MaxAttempts: 3}
+
+	client.CreateContext(context.Background(), Batch{SubmissionName: "x"})
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+
+	calls = 0
+	client.RetryPolicy.RetryNonIdempotent = true
+	client.CreateContext(context.Background(), Batch{SubmissionName: "x"})
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 4 * time.Second}
+	for attempt, max := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 5: 4 * time.Second} {
+		d := p.delay(attempt, Result{})
+		if d < max/2 || d > max {
+			t.Errorf("attempt %d: got %v, want between %v and %v", attempt, d, max/2, max)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+	if d := p.delay(1, Result{Header: header}); d != 3*time.Second {
+		t.Errorf("got %v, want Retry-After to win", d)
+	}
+	header.Set("Retry-After", "3600")
+	if d := p.delay(1, Result{Header: header}); d != 4*time.Second {
+		t.Errorf("got %v, want Retry-After capped at MaxBackoff", d)
+	}
+}
+
+func TestRetryAfterBeyondDeadlineIsNotAwaited(t *testing.T) {
+	var calls int
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client.RetryPolicy = RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := (Batch{}).IndexContext(ctx, client)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("got %v, want a 429", err)
+	}
+	if calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("got %d calls in %v, want 1 without waiting", calls, time.Since(start))
+	}
+}
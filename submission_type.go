@@ -0,0 +1,204 @@
+package espsdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The kinds of media, brands and usages that make up a SubmissionType.
+const (
+	MediaStill = "still"
+	MediaVideo = "video"
+
+	BrandGetty  = "getty"
+	BrandIstock = "istock"
+
+	UsageCreative  = "creative"
+	UsageEditorial = "editorial"
+)
+
+// A SubmissionType describes one of the kinds of Batch ESP accepts, such as
+// getty_editorial_still. RequiredFields are the JSON names of the
+// Contribution fields its field restrictions require, sorted; they are only
+// known for the types found by Client.RefreshSubmissionTypes.
+type SubmissionType struct {
+	Name           string
+	Brand          string
+	Usage          string
+	Media          string
+	RequiredFields []string
+}
+
+// IsVideo reports whether the type is for video rather than stills.
+func (t SubmissionType) IsVideo() bool { return t.Media == MediaVideo }
+
+// IsEditorial reports whether the type is for editorial rather than
+// creative content.
+func (t SubmissionType) IsEditorial() bool { return t.Usage == UsageEditorial }
+
+// IsIstock reports whether the type is for iStock rather than Getty Images.
+func (t SubmissionType) IsIstock() bool { return t.Brand == BrandIstock }
+
+// Requires reports whether field is one of the RequiredFields.
+func (t SubmissionType) Requires(field string) bool {
+	for _, f := range t.RequiredFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSubmissionType describes a type from its name, which ESP composes as
+// brand_usage_media. The parts of a name that do not follow this pattern
+// are left empty.
+func ParseSubmissionType(name string) SubmissionType {
+	t := SubmissionType{Name: name}
+	if parts := strings.Split(name, "_"); len(parts) == 3 {
+		t.Brand, t.Usage, t.Media = parts[0], parts[1], parts[2]
+	}
+	return t
+}
+
+// A submissionTypeSet holds the SubmissionTypes known to a Client. It is
+// shared by the copies of the Client, so that a refresh applies to all.
+type submissionTypeSet struct {
+	mu     sync.RWMutex
+	byName map[string]SubmissionType
+}
+
+func newSubmissionTypeSet(types ...SubmissionType) *submissionTypeSet {
+	s := &submissionTypeSet{}
+	s.set(types)
+	return s
+}
+
+func (s *submissionTypeSet) lookup(name string) (SubmissionType, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.byName[name]
+	return t, ok
+}
+
+func (s *submissionTypeSet) list() []SubmissionType {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var types []SubmissionType
+	for _, t := range s.byName {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+func (s *submissionTypeSet) names() []string {
+	var names []string
+	for _, t := range s.list() {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func (s *submissionTypeSet) set(types []SubmissionType) {
+	byName := make(map[string]SubmissionType, len(types))
+	for _, t := range types {
+		byName[t.Name] = t
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byName = byName
+}
+
+// defaultSubmissionTypes are the types ESP accepts, as far as the SDK
+// knows without asking. They are never changed.
+var defaultSubmissionTypes = newSubmissionTypeSet(
+	ParseSubmissionType("getty_creative_still"),
+	ParseSubmissionType("getty_creative_video"),
+	ParseSubmissionType("getty_editorial_still"),
+	ParseSubmissionType("getty_editorial_video"),
+	ParseSubmissionType("istock_creative_video"),
+)
+
+// LookupSubmissionType returns the built-in SubmissionType with the given
+// name. Client.LookupSubmissionType also knows the types found by
+// Client.RefreshSubmissionTypes.
+func LookupSubmissionType(name string) (SubmissionType, bool) {
+	return defaultSubmissionTypes.lookup(name)
+}
+
+// SubmissionTypes returns the built-in SubmissionTypes, sorted by name.
+func SubmissionTypes() []SubmissionType { return defaultSubmissionTypes.list() }
+
+// SubmissionTypes describes the BatchTypes reported by ESP.
+func (cv ControlledValues) SubmissionTypes() []SubmissionType {
+	var types []SubmissionType
+	for _, name := range cv.BatchTypes {
+		types = append(types, ParseSubmissionType(name))
+	}
+	return types
+}
+
+// submissionTypes returns the set of SubmissionTypes known to the Client,
+// which is the built-in one until RefreshSubmissionTypes is called.
+func (c Client) submissionTypes() *submissionTypeSet {
+	if c.knownTypes == nil {
+		return defaultSubmissionTypes
+	}
+	return c.knownTypes
+}
+
+// LookupSubmissionType returns the SubmissionType with the given name if
+// the Client knows it.
+func (c Client) LookupSubmissionType(name string) (SubmissionType, bool) {
+	return c.submissionTypes().lookup(name)
+}
+
+// SubmissionTypes returns the SubmissionTypes known to the Client, sorted
+// by name.
+func (c Client) SubmissionTypes() []SubmissionType { return c.submissionTypes().list() }
+
+// RefreshSubmissionTypes replaces the SubmissionTypes known to the Client,
+// and to its copies, with the live list from GetControlledValues, so that
+// Batch.Create, Batch.Update and Batch.TypeIsValidFor accept exactly the
+// types ESP currently does when given this Client. The RequiredFields of
+// each type are read from its field restrictions. Other Clients are not
+// affected.
+func (c Client) RefreshSubmissionTypes(ctx context.Context) error {
+	if c.knownTypes == nil {
+		return errors.New("espsdk: RefreshSubmissionTypes needs a Client from NewClient or GetClient")
+	}
+	cv, err := c.GetControlledValuesContext(ctx)
+	if err != nil {
+		return err
+	}
+	types := cv.SubmissionTypes()
+	if len(types) == 0 {
+		Log.Warn("Client.RefreshSubmissionTypes: ESP reported no batch types; keeping the known ones")
+		return nil
+	}
+	for i := range types {
+		restrictions, err := c.GetFieldRestrictionsContext(ctx, FieldRestrictionQuery{FieldRestrictionsType: types[i].Name})
+		if err != nil {
+			return err
+		}
+		types[i].RequiredFields = requiredFields(restrictions.Body)
+	}
+	c.knownTypes.set(types)
+	return nil
+}
+
+// requiredFields returns the JSON names of the fields the restrictions
+// require, either for publishing or outright, sorted.
+func requiredFields(body FieldRestrictionBody) []string {
+	var fields []string
+	for name, r := range restrictionsByField(body) {
+		if r.RequiredForPublish || r.Restriction == RestrictionRequired {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
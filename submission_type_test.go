@@ -0,0 +1,67 @@
+package espsdk_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestParseSubmissionType(t *testing.T) {
+	st := espsdk.ParseSubmissionType("getty_editorial_video")
+	if st.Brand != espsdk.BrandGetty || !st.IsEditorial() || !st.IsVideo() || st.IsIstock() {
+		t.Errorf("got %+v", st)
+	}
+}
+
+func TestRefreshSubmissionTypes(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	srv.ControlledValues["batch_types"] = []string{"getty_creative_still", "istock_creative_still"}
+	srv.FieldRestrictions.Body.Headline = espsdk.FieldRestriction{RequiredForPublish: true}
+	srv.FieldRestrictions.Body.Caption = espsdk.FieldRestriction{Restriction: espsdk.RestrictionRequired}
+	srv.FieldRestrictions.Body.City = espsdk.FieldRestriction{Restriction: espsdk.RestrictionReadOnly}
+	client, other := srv.Client(), srv.Client()
+	if err := client.RefreshSubmissionTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, st := range client.SubmissionTypes() {
+		names = append(names, st.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"getty_creative_still", "istock_creative_still"}) {
+		t.Errorf("got %v", names)
+	}
+	st, _ := client.LookupSubmissionType("istock_creative_still")
+	if !reflect.DeepEqual(st.RequiredFields, []string{"caption", "headline"}) || !st.Requires("headline") {
+		t.Errorf("got required fields %v", st.RequiredFields)
+	}
+	if _, ok := client.LookupSubmissionType("getty_editorial_still"); ok {
+		t.Error("a type no longer reported by ESP should be unknown to the Client")
+	}
+	if _, ok := other.LookupSubmissionType("getty_editorial_still"); !ok {
+		t.Error("a refresh should not affect other Clients")
+	}
+	if _, ok := espsdk.LookupSubmissionType("istock_creative_still"); ok {
+		t.Error("a refresh should not change the built-in types")
+	}
+
+	ctx := context.Background()
+	b := espsdk.Batch{SubmissionName: "x", SubmissionType: "istock_creative_still"}
+	if !b.TypeIsValidFor(client) || b.TypeIsValidFor(other) || b.TypeIsValid() {
+		t.Error("TypeIsValidFor should follow the types known to the Client")
+	}
+	if got := b.ValidTypesFor(client); !reflect.DeepEqual(got, names) {
+		t.Errorf("got valid types %v, want %v", got, names)
+	}
+	if _, err := b.Create(ctx, client); err != nil {
+		t.Errorf("a type reported by ESP should be accepted: %v", err)
+	}
+	if _, err := b.Create(ctx, other); !errors.Is(err, espsdk.ErrInvalidBatchType) {
+		t.Errorf("got %v, want %v", err, espsdk.ErrInvalidBatchType)
+	}
+}
@@ -0,0 +1,164 @@
+package espsdk
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TLSOptions controls how a Client verifies the servers it connects to.
+// The zero value trusts the system's root certificates, as net/http does.
+type TLSOptions struct {
+	// UseBundledRoots trusts the root certificates bundled with the SDK
+	// instead of the system's. Expired certificates in the bundle are
+	// skipped.
+	UseBundledRoots bool
+
+	// ExtraRootsPEM holds additional PEM-encoded root certificates to
+	// trust, such as a corporate CA that intercepts outbound TLS.
+	ExtraRootsPEM []byte
+
+	// PinnedFingerprints are hex-encoded SHA-256 fingerprints, with or
+	// without colons. If any are set, a connection is accepted only if a
+	// certificate in the verified chain matches one of them.
+	PinnedFingerprints []string
+}
+
+// Config builds a tls.Config from the options.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if o.UseBundledRoots || len(o.ExtraRootsPEM) > 0 {
+		var pool *x509.CertPool
+		var err error
+		if o.UseBundledRoots {
+			if pool, err = BundledCertPool(time.Now()); err != nil {
+				return nil, err
+			}
+		} else if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+		if len(o.ExtraRootsPEM) > 0 && !pool.AppendCertsFromPEM(o.ExtraRootsPEM) {
+			return nil, errors.New("espsdk: no certificates found in ExtraRootsPEM")
+		}
+		config.RootCAs = pool
+	}
+
+	if len(o.PinnedFingerprints) > 0 {
+		pins := make(map[string]bool)
+		for _, fp := range o.PinnedFingerprints {
+			normalized := strings.ToLower(strings.Replace(fp, ":", "", -1))
+			if b, err := hex.DecodeString(normalized); err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("espsdk: invalid SHA-256 fingerprint %q", fp)
+			}
+			pins[normalized] = true
+		}
+		config.VerifyPeerCertificate = func(_ [][]byte, chains [][]*x509.Certificate) error {
+			for _, chain := range chains {
+				for _, cert := range chain {
+					if pins[Fingerprint(cert)] {
+						return nil
+					}
+				}
+			}
+			return errors.New("espsdk: server certificate does not match any pinned fingerprint")
+		}
+	}
+	return config, nil
+}
+
+// ConfigureTLS replaces the Client's HTTPClient with one that verifies
+// servers according to the options. It applies to every request the Client
+// sends, including token requests and those of the methods that take a
+// sleepwalker.RESTClient, but not to requests made directly through the
+// embedded sleepwalker.Client. Other settings of an existing HTTPClient,
+// such as its Timeout, are preserved, but a custom http.RoundTripper that
+// is not an *http.Transport cannot be configured.
+func (c *Client) ConfigureTLS(o TLSOptions) error {
+	config, err := o.Config()
+	if err != nil {
+		return err
+	}
+	transport, err := c.ownTransport()
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = config
+	return nil
+}
+
+// Fingerprint returns the hex-encoded SHA-256 fingerprint of the
+// certificate, in the form expected by TLSOptions.PinnedFingerprints.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// BundledCertificates parses the root certificates bundled with the SDK.
+func BundledCertificates() ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := pemCerts
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// BundledCertPool returns a pool of the bundled root certificates that are
+// valid at the given time.
+func BundledCertPool(at time.Time) (*x509.CertPool, error) {
+	certs, err := BundledCertificates()
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		if !certValidAt(cert, at) {
+			Log.WithFields(map[string]interface{}{
+				"subject":   cert.Subject.CommonName,
+				"not_after": cert.NotAfter,
+			}).Debug("BundledCertPool: skipping expired certificate")
+			continue
+		}
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// ExpiredBundledCertificates reports the bundled root certificates that are
+// not valid at the given time.
+func ExpiredBundledCertificates(at time.Time) ([]*x509.Certificate, error) {
+	certs, err := BundledCertificates()
+	if err != nil {
+		return nil, err
+	}
+	var expired []*x509.Certificate
+	for _, cert := range certs {
+		if !certValidAt(cert, at) {
+			expired = append(expired, cert)
+		}
+	}
+	return expired, nil
+}
+
+func certValidAt(cert *x509.Certificate, at time.Time) bool {
+	return !at.Before(cert.NotBefore) && !at.After(cert.NotAfter)
+}
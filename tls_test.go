@@ -0,0 +1,127 @@
+package espsdk
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundledCertificates(t *testing.T) {
+	certs, err := BundledCertificates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) < 100 {
+		t.Errorf("only %d certificates parsed from the bundle", len(certs))
+	}
+
+	expired, err := ExpiredBundledCertificates(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, cert := range expired {
+		if cert.Subject.CommonName == "A-Trust-nQual-03" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("A-Trust-nQual-03 expired in 2015 but was not reported")
+	}
+}
+
+func tlsTestServer(t *testing.T) (*httptest.Server, []byte) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	return srv, certPEM
+}
+
+func TestConfigureTLSTrustsExtraRoots(t *testing.T) {
+	srv, certPEM := tlsTestServer(t)
+
+	var c Client
+	if err := c.ConfigureTLS(TLSOptions{UseBundledRoots: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.HTTPClient.Get(srv.URL); err == nil {
+		t.Errorf("the test server's certificate is not in the bundle and should be rejected")
+	}
+
+	if err := c.ConfigureTLS(TLSOptions{UseBundledRoots: true, ExtraRootsPEM: certPEM}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.HTTPClient.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestConfigureTLSEnforcesPins(t *testing.T) {
+	srv, certPEM := tlsTestServer(t)
+	pin := Fingerprint(srv.Certificate())
+
+	var c Client
+	c.HTTPClient = &http.Client{Timeout: time.Minute}
+	err := c.ConfigureTLS(TLSOptions{ExtraRootsPEM: certPEM, PinnedFingerprints: []string{strings.ToUpper(pin)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HTTPClient.Timeout != time.Minute {
+		t.Errorf("ConfigureTLS should preserve the existing timeout")
+	}
+	resp, err := c.HTTPClient.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	wrong := strings.Repeat("ab", 32)
+	if err := c.ConfigureTLS(TLSOptions{ExtraRootsPEM: certPEM, PinnedFingerprints: []string{wrong}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.HTTPClient.Get(srv.URL); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("got %v, want a pinning failure", err)
+	}
+
+	if err := c.ConfigureTLS(TLSOptions{PinnedFingerprints: []string{"nope"}}); err == nil {
+		t.Errorf("an invalid fingerprint should be rejected")
+	}
+}
+
+func TestConfigureTLSAppliesToLegacyMethods(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/token" {
+			w.Write([]byte(`{"access_token": "t0k3n", "expires_in": 1800}`))
+			return
+		}
+		w.Write([]byte(`{"items": [{"id": "1"}], "meta": {"total_items": 1}}`))
+	}))
+	t.Cleanup(srv.Close)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	client := GetClient("key", "secret", "user", "pass", srv.URL, Log)
+	client.OAuthEndpoint = srv.URL + "/oauth2/token"
+	client.RetryPolicy = NoRetries
+	wrong := strings.Repeat("ab", 32)
+	if err := client.ConfigureTLS(TLSOptions{ExtraRootsPEM: certPEM, PinnedFingerprints: []string{wrong}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Batch{}).Index(client); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("got %v, want a pinning failure", err)
+	}
+
+	pin := Fingerprint(srv.Certificate())
+	if err := client.ConfigureTLS(TLSOptions{ExtraRootsPEM: certPEM, PinnedFingerprints: []string{pin}}); err != nil {
+		t.Fatal(err)
+	}
+	if batches, err := (Batch{}).Index(client); err != nil || len(batches.Items) != 1 {
+		t.Errorf("got %+v, %v", batches, err)
+	}
+}
@@ -0,0 +1,184 @@
+package espsdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A TokenStore saves OAuth tokens so that they can be reused until they
+// expire instead of being requested for every Client. Tokens are keyed by
+// account, so one TokenStore may serve several Clients. Implementations
+// must be safe for concurrent use.
+type TokenStore interface {
+	// Load returns the Token saved under key, or a zero Token if there
+	// is none.
+	Load(key string) (Token, error)
+	// Save replaces the Token saved under key.
+	Save(key string, token Token) error
+}
+
+// A MemoryTokenStore keeps tokens for the life of the process. The zero
+// value is ready to use.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[string]Token)}
+}
+
+// Load returns the Token saved under key.
+func (s *MemoryTokenStore) Load(key string) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[key], nil
+}
+
+// Save replaces the Token saved under key.
+func (s *MemoryTokenStore) Save(key string, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = make(map[string]Token)
+	}
+	s.tokens[key] = token
+	return nil
+}
+
+// A FileTokenStore keeps tokens in a JSON file so that short-lived
+// processes, such as repeated CLI invocations, can share them. The file is
+// replaced atomically on every Save and is readable only by its owner.
+// Saves from separate processes are serialized by a lock file beside it,
+// Path+".lock". A file that cannot be parsed is replaced by the next Save.
+type FileTokenStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileTokenStore returns a FileTokenStore that uses the file at path,
+// which need not exist yet.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+// Load returns the Token saved under key.
+func (s *FileTokenStore) Load(key string) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return Token{}, err
+	}
+	return tokens[key], nil
+}
+
+// Save replaces the Token saved under key, leaving the tokens of other
+// accounts in the file untouched.
+func (s *FileTokenStore) Save(key string, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	tokens, err := s.read()
+	if corruptJSON(err) {
+		// Keep no tokens from a damaged file rather than failing every
+		// Save, which would leave each request to fetch a new token.
+		tokens, err = make(map[string]Token), nil
+	}
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// Lock files older than staleTokenLock were left by a process that exited
+// while saving, since a Save holds the lock only briefly.
+const (
+	staleTokenLock   = 10 * time.Second
+	tokenLockTimeout = 30 * time.Second
+)
+
+// lock creates the lock file, waiting while another process holds it, and
+// returns a function that removes it.
+func (s *FileTokenStore) lock() (func(), error) {
+	path := s.Path + ".lock"
+	deadline := time.Now().Add(tokenLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleTokenLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("espsdk: timed out waiting for %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// corruptJSON reports whether err is from decoding a file that is not
+// JSON, or not JSON of the expected shape.
+func corruptJSON(err error) bool {
+	switch err.(type) {
+	case *json.SyntaxError, *json.UnmarshalTypeError:
+		return true
+	}
+	return false
+}
+
+func (s *FileTokenStore) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return tokens, nil
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
@@ -0,0 +1,131 @@
+package espsdk
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	a, b := NewFileTokenStore(path), NewFileTokenStore(path)
+
+	token := Token{AccessToken: "abc", ExpiresAt: time.Now().Add(time.Hour).Round(0)}
+	if err := a.Save("one", token); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Save("two", Token{AccessToken: "def"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := b.Load("one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "abc" || !got.ExpiresAt.Equal(token.ExpiresAt) {
+		t.Errorf("got %+v, want %+v", got, token)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("token file has mode %v", info.Mode().Perm())
+	}
+}
+
+func TestFileTokenStoreSeparateStoresDoNotLoseSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each store stands in for a separate process.
+			if err := NewFileTokenStore(path).Save(strconv.Itoa(i), Token{AccessToken: "t"}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < 20; i++ {
+		if got, _ := NewFileTokenStore(path).Load(strconv.Itoa(i)); got.AccessToken != "t" {
+			t.Errorf("token %d was lost", i)
+		}
+	}
+}
+
+func TestFileTokenStoreReplacesCorruptFile(t *testing.T) {
+	for _, contents := range []string{"{not json", "[]", `{"one": 42}`} {
+		path := filepath.Join(t.TempDir(), "tokens.json")
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+		s := NewFileTokenStore(path)
+		if err := s.Save("one", Token{AccessToken: "abc"}); err != nil {
+			t.Errorf("%s: %v", contents, err)
+			continue
+		}
+		if got, err := s.Load("one"); err != nil || got.AccessToken != "abc" {
+			t.Errorf("%s: got %+v, %v", contents, got, err)
+		}
+	}
+}
+
+func TestClientsShareStoredToken(t *testing.T) {
+	client, tokenRequests := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	store := NewFileTokenStore(filepath.Join(t.TempDir(), "tokens.json"))
+	client.TokenStore = store
+
+	other := GetClient("key", "secret", "user", "pass", client.apiRoot, Log)
+	other.OAuthEndpoint = client.OAuthEndpoint
+	other.TokenStore = store
+
+	var wg sync.WaitGroup
+	for _, c := range []Client{client, client, other} {
+		wg.Add(1)
+		go func(c Client) {
+			defer wg.Done()
+			if _, err := c.GetControlledValuesContext(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}(c)
+	}
+	wg.Wait()
+	if _, err := other.GetControlledValuesContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The two Clients may race for the first token, but never more.
+	if n := atomic.LoadInt32(tokenRequests); n > 2 {
+		t.Errorf("token was requested %d times", n)
+	}
+}
+
+func TestTokenIsRefreshedBeforeExpiry(t *testing.T) {
+	client, tokenRequests := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	client.TokenStore.Save(client.tokenKey(), Token{
+		AccessToken: "old",
+		ExpiresAt:   time.Now().Add(tokenRefreshWindow / 2),
+	})
+
+	if _, err := client.GetControlledValuesContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(tokenRequests); n != 1 {
+		t.Errorf("token was requested %d times, want 1", n)
+	}
+	stored, _ := client.TokenStore.Load(client.tokenKey())
+	if stored.AccessToken != "t0k3n" {
+		t.Errorf("got stored token %+v", stored)
+	}
+}
@@ -0,0 +1,288 @@
+package espsdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/dysolution/sleepwalker"
+)
+
+// A Findable is any ESP object that can be located by its path.
+type Findable interface {
+	Path() string
+}
+
+// A RESTObject is any ESP object that can be located by its path and
+// serialized into a request body.
+type RESTObject interface {
+	Findable
+	Marshal() ([]byte, error)
+}
+
+// A ContextClient sends requests to the ESP API that are bound to a
+// context.Context, so that deadlines and cancellation propagate into the
+// underlying HTTP requests. Client satisfies ContextClient.
+type ContextClient interface {
+	CreateContext(ctx context.Context, object RESTObject) (Result, error)
+	GetContext(ctx context.Context, object Findable) (Result, error)
+	PutContext(ctx context.Context, object RESTObject, path string) (Result, error)
+	DeleteContext(ctx context.Context, object Findable) (Result, error)
+}
+
+// A Result provides information about the response from the ESP REST API.
+// The embedded sleepwalker.Result holds its StatusCode and Payload.
+type Result struct {
+	sleepwalker.Result
+
+	Verb     string
+	Path     string
+	Header   http.Header
+	Duration time.Duration
+
+	// RateLimitWait is how long the request was held back by the Client's
+	// RateLimiter before it was sent.
+	RateLimitWait time.Duration
+
+	logger *logrus.Logger
+}
+
+// Log returns a log entry describing the completed request.
+func (r Result) Log() *logrus.Entry {
+	logger := r.logger
+	if logger == nil {
+		logger = Log
+	}
+	return logger.WithFields(logrus.Fields{
+		"method":          r.Verb,
+		"path":            r.Path,
+		"status_code":     r.StatusCode,
+		"response_time":   r.Duration,
+		"rate_limit_wait": r.RateLimitWait,
+	})
+}
+
+// Create POSTs the object to its path. Client implements
+// sleepwalker.RESTClient with Create, Get, Put and Delete, so that requests
+// made through it share the transport, retries and authentication of the
+// context-aware requests.
+func (c Client) Create(object sleepwalker.RESTObject) (sleepwalker.Result, error) {
+	result, err := c.CreateContext(context.Background(), object)
+	return result.Result, err
+}
+
+// Get requests the object at its path.
+func (c Client) Get(object sleepwalker.Findable) (sleepwalker.Result, error) {
+	result, err := c.GetContext(context.Background(), object)
+	return result.Result, err
+}
+
+// Put sends the object to the provided path.
+func (c Client) Put(object sleepwalker.RESTObject, path string) (sleepwalker.Result, error) {
+	result, err := c.PutContext(context.Background(), object, path)
+	return result.Result, err
+}
+
+// Delete deletes the object at its path.
+func (c Client) Delete(object sleepwalker.Findable) (sleepwalker.Result, error) {
+	result, err := c.DeleteContext(context.Background(), object)
+	return result.Result, err
+}
+
+// GetPath requests the provided path.
+func (c Client) GetPath(path string) (sleepwalker.Result, error) {
+	result, err := c.GetPathContext(context.Background(), path)
+	return result.Result, err
+}
+
+// GetWithPayload requests the provided path, sending the payload as the
+// body of the GET.
+func (c Client) GetWithPayload(path string, payload []byte) (sleepwalker.Result, error) {
+	result, err := c.GetWithPayloadContext(context.Background(), path, payload)
+	return result.Result, err
+}
+
+// CreateContext POSTs the object to its path.
+func (c Client) CreateContext(ctx context.Context, object RESTObject) (Result, error) {
+	body, err := object.Marshal()
+	if err != nil {
+		return Result{}, err
+	}
+	return c.do(ctx, "POST", object.Path(), body)
+}
+
+// GetContext requests the object at its path.
+func (c Client) GetContext(ctx context.Context, object Findable) (Result, error) {
+	return c.do(ctx, "GET", object.Path(), nil)
+}
+
+// PutContext sends the object to the provided path.
+func (c Client) PutContext(ctx context.Context, object RESTObject, path string) (Result, error) {
+	body, err := object.Marshal()
+	if err != nil {
+		return Result{}, err
+	}
+	return c.do(ctx, "PUT", path, body)
+}
+
+// DeleteContext deletes the object at its path.
+func (c Client) DeleteContext(ctx context.Context, object Findable) (Result, error) {
+	return c.do(ctx, "DELETE", object.Path(), nil)
+}
+
+// GetPathContext requests the provided path.
+func (c Client) GetPathContext(ctx context.Context, path string) (Result, error) {
+	return c.do(ctx, "GET", path, nil)
+}
+
+// GetWithPayloadContext requests the provided path, sending the payload as
+// the body of the GET. Several ESP search endpoints expect their criteria
+// this way.
+func (c Client) GetWithPayloadContext(ctx context.Context, path string, payload []byte) (Result, error) {
+	return c.do(ctx, "GET", path, payload)
+}
+
+// do sends the request, repeating it according to the Client's
+// RetryPolicy.
+func (c Client) do(ctx context.Context, verb, path string, body []byte) (Result, error) {
+	attempts := c.RetryPolicy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	reauthorized := false
+	for attempt := 1; ; attempt++ {
+		result := Result{Verb: verb, Path: path, logger: c.logger}
+		wait, err := c.RateLimiter.Wait(ctx, path)
+		if err != nil {
+			result.RateLimitWait = wait
+			return result, err
+		}
+		token, err := c.accessToken(ctx)
+		if err == nil {
+			result, err = c.send(ctx, verb, path, body, token)
+		}
+		result.RateLimitWait = wait
+		if err == nil && result.StatusCode == http.StatusUnauthorized && !reauthorized {
+			// A shared token may have been revoked early; get a fresh
+			// one and try again without counting it as a retry.
+			reauthorized = true
+			c.invalidateToken(token)
+			attempt--
+			continue
+		}
+		if attempt >= attempts || !c.RetryPolicy.shouldRetry(verb, result, err) {
+			return result, err
+		}
+		backoff := c.RetryPolicy.delay(attempt, result)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			// The retry could not be sent before the deadline, so
+			// report this attempt rather than waiting for nothing.
+			return result, err
+		}
+		result.Log().WithFields(logrus.Fields{
+			"attempt": attempt,
+			"error":   err,
+			"wait":    backoff,
+		}).Warn("Client.retry")
+		if err := sleep(ctx, backoff); err != nil {
+			return result, err
+		}
+	}
+}
+
+// send makes a single attempt at the request.
+func (c Client) send(ctx context.Context, verb, path string, body []byte, token string) (Result, error) {
+	result := Result{Verb: verb, Path: path, logger: c.logger}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, verb, c.apiRoot+path, reader)
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Api-Key", c.credentials.APIKey)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		result.Duration = time.Since(start)
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	result.Payload, err = io.ReadAll(resp.Body)
+	result.Duration = time.Since(start)
+	result.StatusCode = resp.StatusCode
+	result.Header = resp.Header
+	return result, err
+}
+
+func (c Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// legacyClient adapts a sleepwalker.RESTClient that is not a ContextClient,
+// such as a bare *sleepwalker.Client, so that the legacy resource methods
+// can share their implementation with the context-aware ones. The context
+// is not passed on.
+type legacyClient struct {
+	sleepwalker.RESTClient
+}
+
+// contextClient returns the client as a ContextClient. A Client is used
+// directly, so that its transport, retries and authentication apply.
+func contextClient(client sleepwalker.RESTClient) ContextClient {
+	if cc, ok := client.(ContextClient); ok {
+		return cc
+	}
+	return legacyClient{client}
+}
+
+func (lc legacyClient) CreateContext(ctx context.Context, object RESTObject) (Result, error) {
+	result, err := lc.Create(object)
+	return Result{Result: result, Verb: "POST", Path: object.Path()}, err
+}
+
+func (lc legacyClient) GetContext(ctx context.Context, object Findable) (Result, error) {
+	result, err := lc.Get(object)
+	return Result{Result: result, Verb: "GET", Path: object.Path()}, err
+}
+
+func (lc legacyClient) PutContext(ctx context.Context, object RESTObject, path string) (Result, error) {
+	result, err := lc.Put(object, path)
+	return Result{Result: result, Verb: "PUT", Path: path}, err
+}
+
+func (lc legacyClient) DeleteContext(ctx context.Context, object Findable) (Result, error) {
+	result, err := lc.Delete(object)
+	return Result{Result: result, Verb: "DELETE", Path: object.Path()}, err
+}
+
+// checkResponse converts the outcome of a request into an error. Transport
+// errors are returned unchanged and any non-2xx response becomes an
+// *APIError, with the request ID from the response headers when the payload
+// does not include one.
+func checkResponse(result Result, err error) error {
+	if err != nil {
+		return err
+	}
+	err = checkStatus(result.StatusCode, result.Payload)
+	if apiErr, ok := err.(*APIError); ok && apiErr.RequestID == "" {
+		apiErr.RequestID = result.Header.Get("X-Request-Id")
+	}
+	return err
+}
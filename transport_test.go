@@ -0,0 +1,120 @@
+package espsdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testServer(t *testing.T, handler http.HandlerFunc) (Client, *int32) {
+	var tokenRequests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		w.Write([]byte(`{"access_token": "t0k3n", "token_type": "Bearer", "expires_in": "1800"}`))
+	})
+	mux.HandleFunc("/", handler)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := GetClient("key", "secret", "user", "pass", srv.URL, Log)
+	client.OAuthEndpoint = srv.URL + "/oauth2/token"
+	return client, &tokenRequests
+}
+
+func TestGetContextSendsCredentials(t *testing.T) {
+	client, tokenRequests := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k3n" || r.Header.Get("Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"items": [{"id": "42"}], "meta": {"total_items": 1}}`))
+	})
+
+	for i := 0; i < 2; i++ {
+		batches, err := Batch{}.IndexContext(context.Background(), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if batches.Last().ID != "42" {
+			t.Errorf("got %v", batches)
+		}
+	}
+	if n := atomic.LoadInt32(tokenRequests); n != 1 {
+		t.Errorf("token was requested %d times, want 1", n)
+	}
+}
+
+func TestContextCancellationPropagates(t *testing.T) {
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetControlledValuesContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCheckResponseUsesRequestIDHeader(t *testing.T) {
+	client, _ := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-7")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := Contribution{}.IndexContext(context.Background(), client, "1")
+	apiErr, ok := err.(*APIError)
+	if !ok || !IsNotFound(err) {
+		t.Fatalf("got %v, want a 404 APIError", err)
+	}
+	if apiErr.RequestID != "req-7" {
+		t.Errorf("got request id %q", apiErr.RequestID)
+	}
+}
+
+func TestParseTokenAcceptsNumericExpiry(t *testing.T) {
+	now := time.Now()
+	token, err := parseToken([]byte(`{"access_token": "abc", "expires_in": 60}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !token.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("got expiry %v", token.ExpiresAt)
+	}
+}
+
+func TestLegacyMethodsUseClientTransport(t *testing.T) {
+	var calls int
+	client, tokenRequests := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer t0k3n" {
+			t.Errorf("got Authorization %q", r.Header.Get("Authorization"))
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": "c1", "status": "submitted"}`))
+	})
+	client.RetryPolicy = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+
+	result, err := Contribution{SubmissionBatchID: "b1", ID: "c1"}.Submit(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("got status %d after %d calls, want 200 after 2", result.StatusCode, calls)
+	}
+	if *tokenRequests != 1 {
+		t.Errorf("got %d token requests, want 1", *tokenRequests)
+	}
+}
@@ -0,0 +1,268 @@
+package espsdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The FieldRestriction.Restriction values a Validator understands. Others
+// are ignored.
+const (
+	RestrictionRequired   = "required"
+	RestrictionReadOnly   = "read_only"
+	RestrictionNotAllowed = "not_allowed"
+)
+
+// A ValidationReport lists the problems a Validator found with a
+// Contribution, by field. It is empty if none were found.
+type ValidationReport struct {
+	ContributionID string
+	FieldErrors    []FieldError
+}
+
+// Valid reports whether no problems were found.
+func (r ValidationReport) Valid() bool { return len(r.FieldErrors) == 0 }
+
+// Messages returns the messages about the field.
+func (r ValidationReport) Messages(field string) []string {
+	for _, fe := range r.FieldErrors {
+		if fe.Field == field {
+			return fe.Messages
+		}
+	}
+	return nil
+}
+
+// String lists the problems, one field per line.
+func (r ValidationReport) String() string {
+	var lines []string
+	for _, fe := range r.FieldErrors {
+		lines = append(lines, fmt.Sprintf("%s: %s", fe.Field, strings.Join(fe.Messages, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *ValidationReport) add(field, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	for i := range r.FieldErrors {
+		if r.FieldErrors[i].Field == field {
+			r.FieldErrors[i].Messages = append(r.FieldErrors[i].Messages, msg)
+			return
+		}
+	}
+	r.FieldErrors = append(r.FieldErrors, FieldError{Field: field, Messages: []string{msg}})
+}
+
+// A Validator checks Contributions of one SubmissionType for the problems
+// ESP would reject them for, without sending them.
+type Validator struct {
+	SubmissionType   string
+	Restrictions     FieldRestrictionBody
+	ControlledValues ControlledValues
+
+	// ValidateKeywords, if set, looks up the Keywords and Personalities
+	// of each Contribution; Client.ValidateKeywordsContext fits. If nil,
+	// the Valid flags ESP set on them are trusted instead, which suits
+	// Contributions fetched from ESP but not new ones.
+	ValidateKeywords func(ctx context.Context, keywords []string, mediaType string) ([]Keyword, error)
+}
+
+// NewValidator requests the field restrictions and controlled values for
+// the SubmissionType and returns a Validator that also checks keywords
+// through the Client.
+func (c Client) NewValidator(ctx context.Context, submissionType string) (*Validator, error) {
+	restrictions, err := c.GetFieldRestrictionsContext(ctx, FieldRestrictionQuery{FieldRestrictionsType: submissionType})
+	if err != nil {
+		return nil, err
+	}
+	cv, err := c.GetControlledValuesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		SubmissionType:   submissionType,
+		Restrictions:     restrictions.Body,
+		ControlledValues: cv,
+		ValidateKeywords: c.ValidateKeywordsContext,
+	}, nil
+}
+
+// Validate checks the Contribution against the field restrictions, the
+// controlled values of its SubmissionType and the keyword vocabulary. Only
+// the restrictions decide which fields are required, and those on fields a
+// Contribution does not have, such as audio or frame_rate, are ignored. An
+// error is returned only if the keywords could not be looked up.
+func (v Validator) Validate(ctx context.Context, c Contribution) (ValidationReport, error) {
+	report := ValidationReport{ContributionID: c.ID}
+	values, err := fieldValues(c)
+	if err != nil {
+		return report, err
+	}
+
+	required := make(map[string]bool)
+	for field, r := range restrictionsByField(v.Restrictions) {
+		if !contributionFields[field] {
+			continue
+		}
+		_, set := values[field]
+		switch {
+		case r.Restriction == RestrictionReadOnly || r.Restriction == RestrictionNotAllowed:
+			if set {
+				report.add(field, "must not be set")
+			}
+		case r.RequiredForPublish || r.Restriction == RestrictionRequired:
+			if r.DefaultValue == "" {
+				required[field] = true
+			}
+		}
+	}
+	for _, field := range sortedKeys(required) {
+		if _, set := values[field]; !set {
+			report.add(field, "is required")
+		}
+	}
+
+	controlled := v.ControlledValues.ControlledFields[v.SubmissionType]
+	for _, field := range sortedCVKeys(controlled) {
+		allowed := make(map[string]bool)
+		for _, value := range controlled[field] {
+			allowed[value.Value] = true
+		}
+		for _, value := range controlledStrings(values[field]) {
+			if !allowed[value] {
+				report.add(field, "%q is not one of the controlled values", value)
+			}
+		}
+	}
+
+	if err := v.checkKeywords(ctx, &report, "keywords", c.Keywords, c.MediaType); err != nil {
+		return report, err
+	}
+	if err := v.checkKeywords(ctx, &report, "personalities", c.Personalities, c.MediaType); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func (v Validator) checkKeywords(ctx context.Context, report *ValidationReport, field string, keywords []Keyword, mediaType string) error {
+	if len(keywords) == 0 {
+		return nil
+	}
+	if v.ValidateKeywords == nil {
+		for _, kw := range keywords {
+			if !kw.Valid {
+				report.add(field, "%q is not in the vocabulary", kw.Term)
+			}
+		}
+		return nil
+	}
+	var terms []string
+	for _, kw := range keywords {
+		terms = append(terms, kw.Term)
+	}
+	checked, err := v.ValidateKeywords(ctx, terms, mediaType)
+	if err != nil {
+		return err
+	}
+	valid := make(map[string]bool)
+	for _, kw := range checked {
+		if kw.Valid {
+			valid[kw.Term] = true
+		}
+	}
+	for _, term := range terms {
+		if !valid[term] {
+			report.add(field, "%q is not in the vocabulary", term)
+		}
+	}
+	return nil
+}
+
+// fieldValues returns the fields of the Contribution that are set, by their
+// JSON names.
+func fieldValues(c Contribution) (map[string]interface{}, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, err
+	}
+	for field, value := range values {
+		if s, ok := value.(string); ok && strings.TrimSpace(s) == "" {
+			delete(values, field)
+		}
+	}
+	return values, nil
+}
+
+// contributionFields holds the JSON names of the fields of a Contribution.
+var contributionFields = jsonFieldNames(reflect.TypeOf(Contribution{}))
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// restrictionsByField returns the FieldRestrictions in the body by the
+// JSON names of their fields.
+func restrictionsByField(body FieldRestrictionBody) map[string]FieldRestriction {
+	restrictions := make(map[string]FieldRestriction)
+	v := reflect.ValueOf(body)
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		restrictions[name] = v.Field(i).Interface().(FieldRestriction)
+	}
+	return restrictions
+}
+
+// controlledStrings returns the values of a field that are checked against
+// controlled values: a string, the strings in a list, or the term_id or
+// value of objects.
+func controlledStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			out = append(out, controlledStrings(item)...)
+		}
+		return out
+	case map[string]interface{}:
+		for _, key := range []string{"term_id", "value"} {
+			if s, ok := v[key].(string); ok {
+				return []string{s}
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedCVKeys(m map[string][]cv) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package espsdk_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+func TestValidator(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	srv.FieldRestrictions.Body.Caption = espsdk.FieldRestriction{RequiredForPublish: true}
+	srv.FieldRestrictions.Body.CreditLine = espsdk.FieldRestriction{RequiredForPublish: true, DefaultValue: "Getty Images"}
+	srv.FieldRestrictions.Body.Rank = espsdk.FieldRestriction{Restriction: espsdk.RestrictionReadOnly}
+	// A Contribution has no audio field, so this cannot be checked.
+	srv.FieldRestrictions.Body.Audio = espsdk.FieldRestriction{RequiredForPublish: true}
+
+	v, err := srv.Client().NewValidator(context.Background(), "getty_creative_still")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := v.Validate(context.Background(), espsdk.Contribution{
+		Headline:       "Puppy",
+		CollectionCode: "XYZ",
+		Rank:           3,
+		Keywords:       []espsdk.Keyword{{Term: "dog"}, {Term: "dgo"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"caption":          {"is required"},
+		"country_of_shoot": nil,
+		"audio":            nil,
+		"credit_line":      nil,
+		"headline":         nil,
+		"rank":             {"must not be set"},
+		"collection_code":  {`"XYZ" is not one of the controlled values`},
+		"keywords":         {`"dgo" is not in the vocabulary`},
+	}
+	for field, messages := range want {
+		if got := report.Messages(field); !reflect.DeepEqual(got, messages) {
+			t.Errorf("%s: got %q, want %q", field, got, messages)
+		}
+	}
+	if report.Valid() {
+		t.Error("the report should not be valid")
+	}
+
+	report, err = v.Validate(context.Background(), espsdk.Contribution{
+		Headline: "Puppy", Caption: "A puppy", CollectionCode: "ABL",
+		Keywords: []espsdk.Keyword{{Term: "dog"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid() {
+		t.Errorf("got\n%s", report)
+	}
+}
@@ -0,0 +1,164 @@
+package espsdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// A ContributionEventType is a kind of change to a Contribution reported by
+// a ContributionWatcher.
+type ContributionEventType string
+
+// The changes a ContributionWatcher reports. ContributionAdded reports a
+// Contribution that was not in the Batch at the previous poll, and
+// ContributionStatusChanged covers a change to a Status the SDK does not
+// know.
+const (
+	ContributionAdded         ContributionEventType = "added"
+	ContributionSubmitted     ContributionEventType = "submitted"
+	ContributionInReview      ContributionEventType = "in_review"
+	ContributionPublished     ContributionEventType = "published"
+	ContributionRejected      ContributionEventType = "rejected"
+	ContributionPulled        ContributionEventType = "pulled"
+	ContributionStatusChanged ContributionEventType = "status_changed"
+)
+
+// statusEvents maps the Contribution statuses with events of their own.
+var statusEvents = map[string]ContributionEventType{
+	"submitted": ContributionSubmitted,
+	"in_review": ContributionInReview,
+	"published": ContributionPublished,
+	"rejected":  ContributionRejected,
+	"pulled":    ContributionPulled,
+}
+
+// A ContributionEvent reports a change to a Contribution between two polls.
+// Previous is the zero Contribution for ContributionAdded.
+type ContributionEvent struct {
+	Type         ContributionEventType
+	Contribution Contribution
+	Previous     Contribution
+	ObservedAt   time.Time
+}
+
+// A ContributionWatcher polls the Contributions of a Batch and reports how
+// they change.
+//
+//	w := espsdk.WatchContributions(ctx, client, batchID, time.Minute)
+//	for event := range w.Events() {
+//		fmt.Println(event.Type, event.Contribution.ID)
+//	}
+//	if err := w.Err(); err != nil && !errors.Is(err, context.Canceled) {
+//		...
+//	}
+type ContributionWatcher struct {
+	events chan ContributionEvent
+	err    error
+}
+
+// WatchContributions starts polling the Batch's Contributions every
+// interval, or DefaultPollInterval if it is not positive. The first poll
+// only records their state; each later one emits an event for every change
+// found. A poll that fails is logged and tried again at the next interval,
+// unless ESP rejected it in a way that repeating cannot fix, such as for
+// bad credentials or a missing Batch. Polling stops, and the Events channel
+// is closed, when ctx is done or after such a failure.
+func WatchContributions(ctx context.Context, client ContextClient, batchID string, interval time.Duration) *ContributionWatcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	w := &ContributionWatcher{events: make(chan ContributionEvent)}
+	go w.run(ctx, client, batchID, interval)
+	return w
+}
+
+// Events returns the channel on which changes are reported.
+func (w *ContributionWatcher) Events() <-chan ContributionEvent { return w.events }
+
+// Err returns the reason polling stopped. It must only be called after the
+// Events channel has been closed.
+func (w *ContributionWatcher) Err() error { return w.err }
+
+func (w *ContributionWatcher) run(ctx context.Context, client ContextClient, batchID string, interval time.Duration) {
+	defer close(w.events)
+	var known map[string]Contribution
+	for {
+		contributions, err := NewContributionIterator(client, batchID).All(ctx)
+		switch {
+		case err == nil:
+			now := time.Now()
+			current := make(map[string]Contribution, len(contributions))
+			for _, c := range contributions {
+				current[c.ID] = c
+				if known == nil {
+					continue
+				}
+				previous, ok := known[c.ID]
+				types := []ContributionEventType{ContributionAdded}
+				if ok {
+					types = changes(previous, c)
+				}
+				for _, t := range types {
+					select {
+					case w.events <- ContributionEvent{t, c, previous, now}:
+					case <-ctx.Done():
+						w.err = ctx.Err()
+						return
+					}
+				}
+			}
+			known = current
+		case ctx.Err() != nil || permanent(err):
+			w.err = err
+			return
+		default:
+			Log.WithField("error", err).Warn("ContributionWatcher")
+		}
+		if err := sleep(ctx, interval); err != nil {
+			w.err = err
+			return
+		}
+	}
+}
+
+// permanent reports whether err is a response from ESP that repeating the
+// request cannot change: a client error other than a timeout or throttling.
+func permanent(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	status := apiErr.StatusCode
+	return status >= 400 && status < 500 &&
+		status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// changes returns the events that describe how a Contribution changed
+// between two polls, each at most once.
+func changes(previous, current Contribution) []ContributionEventType {
+	var types []ContributionEventType
+	seen := make(map[ContributionEventType]bool)
+	add := func(t ContributionEventType) {
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	if current.Status != previous.Status {
+		if t, ok := statusEvents[current.Status]; ok {
+			add(t)
+		} else {
+			add(ContributionStatusChanged)
+		}
+	}
+	if previous.PublishedAt == nil && current.PublishedAt != nil ||
+		!previous.ReadyForSale && current.ReadyForSale {
+		add(ContributionPublished)
+	}
+	if previous.PulledReason == "" && current.PulledReason != "" {
+		add(ContributionPulled)
+	}
+	return types
+}
@@ -0,0 +1,134 @@
+package espsdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dysolution/espsdk"
+	"github.com/dysolution/espsdk/esptest"
+)
+
+// pollingClient reports each GET the watcher makes on polled, and fails the
+// nth one with errs[n] when that is set.
+type pollingClient struct {
+	espsdk.ContextClient
+	polled chan int
+	errs   map[int]error
+	n      int
+}
+
+func newPollingClient(c espsdk.ContextClient, errs map[int]error) *pollingClient {
+	return &pollingClient{ContextClient: c, polled: make(chan int, 100), errs: errs}
+}
+
+func (c *pollingClient) GetContext(ctx context.Context, object espsdk.Findable) (espsdk.Result, error) {
+	defer func() { c.polled <- c.n; c.n++ }()
+	if err := c.errs[c.n]; err != nil {
+		return espsdk.Result{}, err
+	}
+	return c.ContextClient.GetContext(ctx, object)
+}
+
+func nextEvent(t *testing.T, ctx context.Context, w *espsdk.ContributionWatcher) espsdk.ContributionEvent {
+	t.Helper()
+	select {
+	case event, ok := <-w.Events():
+		if !ok {
+			t.Fatalf("watcher stopped: %v", w.Err())
+		}
+		return event
+	case <-ctx.Done():
+		t.Fatal("no event")
+	}
+	return espsdk.ContributionEvent{}
+}
+
+func TestWatchContributions(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_editorial_still"})
+	c := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Status: "submitted"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client := newPollingClient(srv.Client(), nil)
+	w := espsdk.WatchContributions(ctx, client, b.ID, 5*time.Millisecond)
+
+	steps := []struct {
+		change func(*espsdk.Contribution)
+		want   []espsdk.ContributionEventType
+	}{
+		{func(c *espsdk.Contribution) { c.Status = "in_review" },
+			[]espsdk.ContributionEventType{espsdk.ContributionInReview}},
+		{func(c *espsdk.Contribution) {
+			now := time.Now()
+			c.Status, c.PublishedAt, c.ReadyForSale = "published", &now, true
+		}, []espsdk.ContributionEventType{espsdk.ContributionPublished}},
+		{func(c *espsdk.Contribution) { c.Status, c.PulledReason = "inactive", "model release withdrawn" },
+			[]espsdk.ContributionEventType{espsdk.ContributionStatusChanged, espsdk.ContributionPulled}},
+	}
+	<-client.polled // the first poll has recorded the initial state
+	for _, step := range steps {
+		srv.UpdateContribution(b.ID, c.ID, step.change)
+		for _, want := range step.want {
+			if event := nextEvent(t, ctx, w); event.Type != want || event.Contribution.ID != c.ID {
+				t.Fatalf("got %s for %s, want %s", event.Type, event.Contribution.ID, want)
+			}
+		}
+	}
+
+	added := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Status: "pending"})
+	if event := nextEvent(t, ctx, w); event.Type != espsdk.ContributionAdded || event.Contribution.ID != added.ID {
+		t.Fatalf("got %s for %s, want %s for %s", event.Type, event.Contribution.ID, espsdk.ContributionAdded, added.ID)
+	}
+
+	cancel()
+	for range w.Events() {
+	}
+	if !errors.Is(w.Err(), context.Canceled) {
+		t.Errorf("got %v", w.Err())
+	}
+}
+
+func TestWatchContributionsKeepsPollingAfterFailure(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_editorial_still"})
+	c := srv.AddContribution(espsdk.Contribution{SubmissionBatchID: b.ID, Status: "submitted"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client := newPollingClient(srv.Client(), map[int]error{
+		1: &espsdk.APIError{StatusCode: http.StatusServiceUnavailable},
+		2: &espsdk.APIError{StatusCode: http.StatusTooManyRequests},
+	})
+	w := espsdk.WatchContributions(ctx, client, b.ID, 5*time.Millisecond)
+
+	<-client.polled
+	srv.UpdateContribution(b.ID, c.ID, func(c *espsdk.Contribution) { c.Status = "in_review" })
+	if event := nextEvent(t, ctx, w); event.Type != espsdk.ContributionInReview {
+		t.Errorf("got %s, want %s", event.Type, espsdk.ContributionInReview)
+	}
+}
+
+func TestWatchContributionsStopsOnPermanentFailure(t *testing.T) {
+	srv := esptest.NewServer()
+	defer srv.Close()
+	b := srv.AddBatch(espsdk.Batch{SubmissionName: "x", SubmissionType: "getty_editorial_still"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client := newPollingClient(srv.Client(), map[int]error{
+		1: &espsdk.APIError{StatusCode: http.StatusNotFound},
+	})
+	w := espsdk.WatchContributions(ctx, client, b.ID, 5*time.Millisecond)
+
+	for range w.Events() {
+	}
+	if !espsdk.IsNotFound(w.Err()) {
+		t.Errorf("got %v, want a not found error", w.Err())
+	}
+}